@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/lint"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+const configFilename = ".pkiplot.yaml"
+
+// config is the content of a .pkiplot.yaml file. Every setting corresponds to
+// a command line flag, which takes precedence if it is given explicitly.
+type config struct {
+	// Sources are used when no sources are given on the command line;
+	// relative paths are resolved relative to the config file.
+	Sources []string `json:"sources,omitempty"`
+
+	Namespace         string   `json:"namespace,omitempty"`
+	IncludeNamespaces []string `json:"includeNamespaces,omitempty"`
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+	Selector          string   `json:"selector,omitempty"`
+	Lenient           *bool    `json:"lenient,omitempty"`
+	MaxDocumentSize   int      `json:"maxDocumentSize,omitempty"`
+
+	// Label is a node label template, see --label and --label-preset.
+	Label       string `json:"label,omitempty"`
+	LabelPreset string `json:"labelPreset,omitempty"`
+
+	// Theme is the name of a built-in theme or the path to a theme file,
+	// relative to the config file.
+	Theme string `json:"theme,omitempty"`
+
+	ClusterResourceNamespace string `json:"clusterResourceNamespace,omitempty"`
+	ShowSecrets              *bool  `json:"showSecrets,omitempty"`
+	ShowSynthetics           *bool  `json:"showSynthetics,omitempty"`
+
+	// Formats are the output formats, optionally with a filename each
+	// (e.g. "mermaid=pki.mmd"), see --format.
+	Formats []string `json:"formats,omitempty"`
+	Output  string   `json:"output,omitempty"`
+
+	// Renderers holds renderer-specific options, keyed by renderer name and
+	// then by the flag name without the renderer prefix, e.g.
+	// {"mermaid": {"show-type": true}} for --mermaid-show-type.
+	Renderers map[string]map[string]any `json:"renderers,omitempty"`
+
+	// Styles overrides the style of node classes (see --mermaid-class-style).
+	Styles map[string]string `json:"styles,omitempty"`
+
+	// Lint enables or disables lint rules by name; all rules are enabled by
+	// default (see --disable-lint).
+	Lint map[string]bool `json:"lint,omitempty"`
+}
+
+// findConfig looks for a config file in the working directory and all of
+// its parents and returns an empty string if none was found.
+func findConfig() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, configFilename)
+
+		_, err := os.Stat(candidate)
+		if err == nil {
+			return candidate, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+func loadConfig(filename string) (*config, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &config{}
+	if err := yaml.UnmarshalStrict(content, cfg); err != nil {
+		return nil, err
+	}
+
+	// make sources independent from the working directory
+	baseDir := filepath.Dir(filename)
+	for i, source := range cfg.Sources {
+		if source != "-" && !filepath.IsAbs(source) {
+			cfg.Sources[i] = filepath.Join(baseDir, source)
+		}
+	}
+
+	if cfg.Theme != "" && !slices.Contains(theme.Names(), cfg.Theme) && !filepath.IsAbs(cfg.Theme) {
+		cfg.Theme = filepath.Join(baseDir, cfg.Theme)
+	}
+
+	return cfg, nil
+}
+
+// apply sets all flags that were not explicitly given on the command line
+// to the values from the config.
+func (c *config) apply(fs *pflag.FlagSet) error {
+	settings := map[string]any{}
+
+	setString := func(flag string, value string) {
+		if value != "" {
+			settings[flag] = value
+		}
+	}
+
+	setBool := func(flag string, value *bool) {
+		if value != nil {
+			settings[flag] = *value
+		}
+	}
+
+	setInt := func(flag string, value int) {
+		if value != 0 {
+			settings[flag] = value
+		}
+	}
+
+	setStrings := func(flag string, value []string) {
+		if value != nil {
+			settings[flag] = value
+		}
+	}
+
+	setString("namespace", c.Namespace)
+	setStrings("include-namespace", c.IncludeNamespaces)
+	setStrings("exclude-namespace", c.ExcludeNamespaces)
+	setString("selector", c.Selector)
+	setBool("lenient", c.Lenient)
+	setInt("max-document-size", c.MaxDocumentSize)
+	setString("label", c.Label)
+	setString("label-preset", c.LabelPreset)
+	setString("theme", c.Theme)
+	setString("cluster-resource-namespace", c.ClusterResourceNamespace)
+	setBool("show-secrets", c.ShowSecrets)
+	setBool("show-synthetics", c.ShowSynthetics)
+	setStrings("format", c.Formats)
+	setString("output", c.Output)
+
+	for renderer, options := range c.Renderers {
+		for name, value := range options {
+			settings[renderer+"-"+name] = value
+		}
+	}
+
+	if len(c.Styles) > 0 {
+		var styles []string
+		for _, class := range sets.List(sets.KeySet(c.Styles)) {
+			styles = append(styles, class+"="+c.Styles[class])
+		}
+
+		settings["mermaid-class-style"] = styles
+	}
+
+	if len(c.Lint) > 0 {
+		if err := lint.ValidateRuleNames(sets.List(sets.KeySet(c.Lint))); err != nil {
+			return err
+		}
+
+		disabled := []string{}
+		for _, rule := range sets.List(sets.KeySet(c.Lint)) {
+			if !c.Lint[rule] {
+				disabled = append(disabled, rule)
+			}
+		}
+
+		settings["disable-lint"] = disabled
+	}
+
+	for _, name := range sets.List(sets.KeySet(settings)) {
+		if err := setFlagDefault(fs, name, settings[name]); err != nil {
+			return fmt.Errorf("invalid setting for --%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func setFlagDefault(fs *pflag.FlagSet, name string, value any) error {
+	flag := fs.Lookup(name)
+	if flag == nil {
+		return errors.New("no such flag")
+	}
+
+	// command line flags win over the config file
+	if flag.Changed {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []string:
+		return replaceSliceFlag(flag, v)
+
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+
+		return replaceSliceFlag(flag, values)
+
+	case map[string]any:
+		var values []string
+		for _, key := range sets.List(sets.KeySet(v)) {
+			values = append(values, fmt.Sprintf("%s=%v", key, v[key]))
+		}
+
+		return replaceSliceFlag(flag, values)
+
+	case bool:
+		return flag.Value.Set(strconv.FormatBool(v))
+
+	default:
+		return flag.Value.Set(fmt.Sprint(v))
+	}
+}
+
+func replaceSliceFlag(flag *pflag.Flag, values []string) error {
+	sliceValue, ok := flag.Value.(pflag.SliceValue)
+	if !ok {
+		return fmt.Errorf("flag does not accept a list of values")
+	}
+
+	return sliceValue.Replace(slices.Clone(values))
+}
@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/pkiplot"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/render/tree"
+)
+
+var (
+	// beginMarker matches e.g. `<!-- pkiplot:begin name=kcp source=pki.yaml -->`.
+	beginMarker = regexp.MustCompile(`^\s*<!--\s*pkiplot:begin\b(.*?)-->\s*$`)
+	endMarker   = regexp.MustCompile(`^\s*<!--\s*pkiplot:end\b.*-->\s*$`)
+
+	// markerAttribute matches key=value and key="value with spaces".
+	markerAttribute = regexp.MustCompile(`(\w+)=(?:"([^"]*)"|(\S+))`)
+
+	// codeFence matches the start or end of a fenced code block, e.g. ```go.
+	codeFence = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})(.*)$")
+
+	errStale = errors.New("file is out of date")
+)
+
+// fenceLanguages are the code block languages for formats whose name is
+// not a common language identifier.
+var fenceLanguages = map[string]string{
+	"graphviz":  "dot",
+	"cytoscape": "json",
+	"tree":      "text",
+}
+
+// binaryFormats cannot be embedded into Markdown files.
+var binaryFormats = []string{"png"}
+
+// marker is a region in a Markdown file that contains a generated diagram.
+type marker struct {
+	name    string
+	format  string
+	sources []string
+	// begin and end are the line indices of the marker comments
+	begin int
+	end   int
+}
+
+// injectDiagrams replaces the content between all markers in the given
+// Markdown file with freshly rendered diagrams. Markers can specify their own
+// sources (relative to the Markdown file) and format, otherwise the given
+// sources and mermaid are used. In check mode, the file is not modified and
+// errStale is returned if it would have been changed.
+func injectDiagrams(ctx context.Context, filename string, sources []pkiplot.Source, opts []pkiplot.Option, check bool) error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+
+	markers, err := findMarkers(lines)
+	if err != nil {
+		return err
+	}
+
+	if len(markers) == 0 {
+		return errors.New("no pkiplot:begin markers found")
+	}
+
+	// many markers share the same sources
+	graphs := map[string]pkigraph.Graph{}
+
+	var output strings.Builder
+	lastLine := 0
+
+	for _, m := range markers {
+		markerSources := sources
+		if len(m.sources) > 0 {
+			markerSources = nil
+			for _, source := range m.sources {
+				if !filepath.IsAbs(source) {
+					source = filepath.Join(filepath.Dir(filename), source)
+				}
+
+				markerSources = append(markerSources, pkiplot.FromPath(source))
+			}
+		}
+
+		if len(markerSources) == 0 {
+			return fmt.Errorf("marker %s has no source and no sources were given on the command line", m)
+		}
+
+		cacheKey := fmt.Sprintf("%v", markerSources)
+
+		graph, ok := graphs[cacheKey]
+		if !ok {
+			graph, _, err = pkiplot.Load(ctx, markerSources, opts...)
+			if err != nil {
+				return fmt.Errorf("failed to load sources for marker %s: %w", m, err)
+			}
+
+			graphs[cacheKey] = graph
+		}
+
+		renderer, ok := render.Get(m.format)
+		if !ok {
+			return fmt.Errorf("marker %s uses unknown format %q", m, m.format)
+		}
+
+		// ANSI colors have no place in Markdown
+		if m.format == "tree" {
+			renderer = tree.New(tree.Options{NoColor: true})
+		}
+
+		var rendered bytes.Buffer
+		if err := pkiplot.Render(ctx, &rendered, graph, append(slices.Clone(opts), pkiplot.WithRenderer(renderer))...); err != nil {
+			return fmt.Errorf("failed to render marker %s: %w", m, err)
+		}
+
+		language, ok := fenceLanguages[m.format]
+		if !ok {
+			language = m.format
+		}
+
+		// keep everything up to and including the begin marker
+		output.WriteString(strings.Join(lines[lastLine:m.begin+1], ""))
+		output.WriteString("```" + language + "\n")
+		output.WriteString(strings.TrimRight(rendered.String(), "\n") + "\n")
+		output.WriteString("```\n")
+
+		lastLine = m.end
+	}
+
+	output.WriteString(strings.Join(lines[lastLine:], ""))
+
+	updated := output.String()
+	if updated == string(content) {
+		return nil
+	}
+
+	if check {
+		return errStale
+	}
+
+	return writeFileAtomic(filename, func(w io.Writer) error {
+		_, err := io.WriteString(w, updated)
+		return err
+	})
+}
+
+// findMarkers returns all markers in the file. Markers in fenced code blocks
+// (e.g. in documentation about markers) are ignored.
+func findMarkers(lines []string) ([]marker, error) {
+	var (
+		markers []marker
+		current *marker
+		// fence is the opening fence of the current code block
+		fence string
+	)
+
+	for i, line := range lines {
+		if match := codeFence.FindStringSubmatch(strings.TrimRight(line, "\r\n")); match != nil {
+			switch {
+			case fence == "":
+				fence = match[1]
+			// a closing fence uses the same character, is at least as long
+			// as the opening one and has no info string
+			case match[1][0] == fence[0] && len(match[1]) >= len(fence) && strings.TrimSpace(match[2]) == "":
+				fence = ""
+			}
+
+			continue
+		}
+
+		if fence != "" {
+			continue
+		}
+
+		if match := beginMarker.FindStringSubmatch(line); match != nil {
+			if current != nil {
+				return nil, fmt.Errorf("line %d: marker %s is not closed before the next begins", i+1, current)
+			}
+
+			m, err := parseMarker(match[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+			m.begin = i
+			current = m
+			continue
+		}
+
+		if endMarker.MatchString(line) {
+			if current == nil {
+				return nil, fmt.Errorf("line %d: pkiplot:end without pkiplot:begin", i+1)
+			}
+
+			current.end = i
+			markers = append(markers, *current)
+			current = nil
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("marker %s is never closed", current)
+	}
+
+	return markers, nil
+}
+
+func parseMarker(attributes string) (*marker, error) {
+	m := &marker{format: pkiplot.DefaultFormat}
+
+	for _, match := range markerAttribute.FindAllStringSubmatch(attributes, -1) {
+		value := match[2] + match[3]
+
+		switch match[1] {
+		case "name":
+			m.name = value
+		case "format":
+			m.format = value
+		case "source":
+			m.sources = strings.Split(value, ",")
+		default:
+			return nil, fmt.Errorf("unknown marker attribute %q", match[1])
+		}
+	}
+
+	for _, format := range binaryFormats {
+		if m.format == format {
+			return nil, fmt.Errorf("format %q cannot be embedded into Markdown", format)
+		}
+	}
+
+	return m, nil
+}
+
+func (m marker) String() string {
+	if m.name != "" {
+		return fmt.Sprintf("%q", m.name)
+	}
+
+	return fmt.Sprintf("in line %d", m.begin+1)
+}
@@ -4,15 +4,27 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"os/signal"
 	"runtime"
+	"slices"
 
 	"github.com/spf13/pflag"
 
+	"go.xrstf.de/pkiplot/pkg/label"
+	"go.xrstf.de/pkiplot/pkg/lint"
 	"go.xrstf.de/pkiplot/pkg/loader"
 	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/pkiplot"
 	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // These variables get set by ldflags during compilation.
@@ -39,15 +51,43 @@ func printVersion() {
 }
 
 type globalOptions struct {
-	namespace    string
-	graphOptions pkigraph.Options
-	format       string
-	version      bool
+	configFile        string
+	namespace         string
+	includeNamespaces []string
+	excludeNamespaces []string
+	selector          string
+	label             string
+	labelPreset       string
+	theme             string
+	disabledLint      []string
+	lenient           bool
+	maxDocumentSize   int
+	watch             bool
+	listen            string
+	check             bool
+	graphOptions      pkigraph.Options
+	formats           []string
+	output            string
+	version           bool
 }
 
 func (o *globalOptions) AddFlags(fs *pflag.FlagSet) {
-	fs.StringVarP(&o.namespace, "namespace", "n", o.namespace, "Only include namespace-scoped resources in this namespace (also the default namespace for resources without namespace set)")
-	fs.StringVarP(&o.format, "format", "f", o.format, fmt.Sprintf("Output format (one of %v)", render.All()))
+	fs.StringVarP(&o.configFile, "config", "c", o.configFile, fmt.Sprintf("Path to a config file (default: %s in the working directory or any of its parents)", configFilename))
+	fs.StringVarP(&o.namespace, "namespace", "n", o.namespace, "Default namespace for namespace-scoped resources without namespace set")
+	fs.StringSliceVarP(&o.includeNamespaces, "include-namespace", "", o.includeNamespaces, "Only include namespace-scoped resources in namespaces matching these glob patterns (can be given multiple times)")
+	fs.StringSliceVarP(&o.excludeNamespaces, "exclude-namespace", "", o.excludeNamespaces, "Exclude namespace-scoped resources in namespaces matching these glob patterns (can be given multiple times)")
+	fs.StringVarP(&o.selector, "selector", "l", o.selector, "Only include resources matching this Kubernetes label selector (e.g. app=kcp)")
+	fs.StringVarP(&o.label, "label", "", o.label, "Go template for node labels, where \\n starts a new line (e.g. '{{.Name}}\\n{{join .Spec.DNSNames \", \"}}')")
+	fs.StringVarP(&o.labelPreset, "label-preset", "", o.labelPreset, fmt.Sprintf("Predefined node label template, one of %v (ignored if --label is given)", label.PresetNames()))
+	fs.StringVarP(&o.theme, "theme", "", o.theme, fmt.Sprintf("Color theme, one of %v or the path to a theme file", theme.Names()))
+	fs.StringArrayVarP(&o.formats, "format", "f", o.formats, fmt.Sprintf("Output format (one of %v), optionally followed by =<filename> (can be given multiple times)", render.All()))
+	fs.StringVarP(&o.output, "output", "o", o.output, "Write the output to this file instead of stdout")
+	fs.StringSliceVarP(&o.disabledLint, "disable-lint", "", o.disabledLint, fmt.Sprintf("Lint rules to skip (any of %v)", lint.RuleNames()))
+	fs.BoolVarP(&o.lenient, "lenient", "", o.lenient, "Skip invalid documents and objects (printing warnings) instead of failing")
+	fs.IntVarP(&o.maxDocumentSize, "max-document-size", "", o.maxDocumentSize, "Maximum size of a single YAML document in bytes, larger documents are invalid (0 means no limit)")
+	fs.BoolVarP(&o.watch, "watch", "w", o.watch, "Keep running and re-render whenever a source file changes")
+	fs.StringVarP(&o.listen, "listen", "", o.listen, "Address for the preview server to listen on (only for 'pkiplot serve')")
+	fs.BoolVarP(&o.check, "check", "", o.check, "Only check whether the file is up to date and fail if not (only for 'pkiplot inject')")
 	fs.BoolVarP(&o.version, "version", "V", o.version, "Show version info and exit immediately")
 
 	fs.StringVarP(&o.graphOptions.ClusterResourceNamespace, "cluster-resource-namespace", "", o.graphOptions.ClusterResourceNamespace, "cert-manager's cluster resource namespace, used to find secrets referenced by cluster-scoped objects")
@@ -63,9 +103,11 @@ func main() {
 	}
 
 	opts := globalOptions{
-		format: "mermaid",
+		formats: []string{pkiplot.DefaultFormat},
+		theme:   theme.DefaultName,
+		listen:  "127.0.0.1:8080",
 		graphOptions: pkigraph.Options{
-			ClusterResourceNamespace: "cert-manager",
+			ClusterResourceNamespace: pkiplot.DefaultClusterResourceNamespace,
 		},
 	}
 
@@ -77,33 +119,226 @@ func main() {
 		return
 	}
 
+	configFile := opts.configFile
+	if configFile == "" {
+		var err error
+
+		configFile, err = findConfig()
+		if err != nil {
+			log.Fatalf("Failed to find config file: %v.", err)
+		}
+	}
+
 	args := pflag.Args()
-	if len(args) == 0 {
+
+	// "pkiplot serve [sources...]" starts the preview server instead and
+	// "pkiplot inject FILE [sources...]" updates diagrams in a Markdown file
+	var command string
+	if len(args) > 0 && (args[0] == "serve" || args[0] == "inject") {
+		command = args[0]
+		args = args[1:]
+	}
+
+	serveMode := command == "serve"
+	injectMode := command == "inject"
+
+	var injectFile string
+	if injectMode {
+		if len(args) == 0 {
+			log.Fatal("No Markdown file provided.")
+		}
+
+		injectFile = args[0]
+		args = args[1:]
+	}
+
+	if configFile != "" {
+		cfg, err := loadConfig(configFile)
+		if err != nil {
+			log.Fatalf("Failed to load config file %s: %v.", configFile, err)
+		}
+
+		if err := cfg.apply(pflag.CommandLine); err != nil {
+			log.Fatalf("Invalid config file %s: %v.", configFile, err)
+		}
+
+		if len(args) == 0 {
+			args = cfg.Sources
+		}
+	}
+
+	// markers in the Markdown file can specify their own sources
+	if len(args) == 0 && !injectMode {
 		log.Fatal("No input file(s) provided.")
 	}
 
-	renderer, exists := render.Get(opts.format)
-	if !exists {
-		log.Fatalf("Invalid output format %q, must be one of %v.", opts.format, render.All())
+	targets, err := parseOutputs(opts.formats, opts.output)
+	if err != nil {
+		log.Fatalf("Invalid output configuration: %v.", err)
+	}
+
+	usedRenderers := []render.Renderer{}
+	if serveMode || injectMode {
+		// the server offers multiple formats and markers can use any format
+		for _, name := range allRenderers {
+			r, _ := render.Get(name)
+			usedRenderers = append(usedRenderers, r)
+		}
+	} else {
+		for _, target := range targets {
+			usedRenderers = append(usedRenderers, target.renderer)
+		}
+	}
+
+	for _, r := range usedRenderers {
+		if err := r.ValidateFlags(); err != nil {
+			log.Fatalf("Invalid command line flags: %v.", err)
+		}
+	}
+
+	if err := lint.ValidateRuleNames(opts.disabledLint); err != nil {
+		log.Fatalf("Invalid lint rules: %v.", err)
+	}
+
+	selector, err := labels.Parse(opts.selector)
+	if err != nil {
+		log.Fatalf("Invalid label selector: %v.", err)
+	}
+
+	var labelTemplate *label.Template
+	switch {
+	case opts.label != "":
+		labelTemplate, err = label.New(opts.label)
+	case opts.labelPreset != "":
+		labelTemplate, err = label.FromPreset(opts.labelPreset)
+	}
+	if err != nil {
+		log.Fatalf("Invalid label template: %v.", err)
+	}
+
+	th, err := theme.Get(opts.theme)
+	if err != nil {
+		log.Fatalf("Invalid theme: %v.", err)
+	}
+
+	sources := make([]pkiplot.Source, 0, len(args))
+	for _, arg := range args {
+		sources = append(sources, pkiplot.FromPath(arg))
 	}
 
-	if err := renderer.ValidateFlags(); err != nil {
-		log.Fatalf("Invalid command line flags: %v.", err)
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	plotOpts := []pkiplot.Option{
+		pkiplot.WithDefaultNamespace(opts.namespace),
+		pkiplot.WithIncludedNamespaces(opts.includeNamespaces...),
+		pkiplot.WithExcludedNamespaces(opts.excludeNamespaces...),
+		pkiplot.WithLabelSelector(selector),
+		pkiplot.WithLenient(opts.lenient),
+		pkiplot.WithMaxDocumentSize(opts.maxDocumentSize),
+		pkiplot.WithClusterResourceNamespace(opts.graphOptions.ClusterResourceNamespace),
+		pkiplot.WithSecrets(opts.graphOptions.ShowSecrets),
+		pkiplot.WithSynthetics(opts.graphOptions.ShowSynthetics),
+		pkiplot.WithLabels(labelTemplate),
+		pkiplot.WithTheme(th),
+		pkiplot.WithDisabledLintRules(opts.disabledLint...),
+		pkiplot.WithWarningHandler(func(warning loader.Warning) {
+			log.Printf("Warning: %v", warning)
+		}),
 	}
 
-	loaderOpts := loader.NewDefaultOptions()
-	loaderOpts.Namespace = opts.namespace
+	if injectMode {
+		err := injectDiagrams(ctx, injectFile, sources, plotOpts, opts.check)
+		if errors.Is(err, errStale) {
+			log.Fatalf("%s is out of date, run `pkiplot inject %s` to update it.", injectFile, injectFile)
+		}
+		if err != nil {
+			log.Fatalf("Failed to update %s: %v.", injectFile, err)
+		}
 
-	pki, err := loader.LoadPKI(args, loaderOpts)
+		return
+	}
+
+	if serveMode {
+		if err := runServer(ctx, opts.listen, args, sources, plotOpts, th); err != nil {
+			log.Fatalf("Failed to run preview server: %v.", err)
+		}
+
+		return
+	}
+
+	if !opts.watch {
+		if err := plot(ctx, sources, plotOpts, opts.disabledLint, targets); err != nil {
+			log.Fatalf("Error: %v.", err)
+		}
+
+		return
+	}
+
+	var outputs []string
+	for _, target := range targets {
+		if target.filename != "" {
+			outputs = append(outputs, target.filename)
+		}
+	}
+
+	watcher, err := newSourceWatcher(args, loader.NewDefaultOptions().FileExtensions, outputs)
 	if err != nil {
-		log.Fatalf("Failed to load all sources: %v.", err)
+		log.Fatalf("Failed to watch sources: %v.", err)
 	}
 
-	graph := pkigraph.NewFromPKI(pki, opts.graphOptions)
-	rendered, err := renderer.RenderGraph(graph)
+	replot := func() {
+		if err := plot(ctx, sources, plotOpts, opts.disabledLint, targets); err != nil {
+			log.Printf("Error: %v.", err)
+		} else {
+			log.Printf("PKI rendered successfully.")
+		}
+	}
+
+	replot()
+	log.Printf("Watching %d source(s) for changes, press Ctrl+C to stop…", len(args))
+
+	if err := watcher.Run(ctx, replot); err != nil {
+		log.Fatalf("Failed to watch sources: %v.", err)
+	}
+}
+
+// plot runs the entire load → graph → render pipeline once and prints all
+// lint findings that are not disabled.
+func plot(ctx context.Context, sources []pkiplot.Source, opts []pkiplot.Option, disabledLint []string, targets []outputTarget) error {
+	graph, warnings, err := pkiplot.Load(ctx, sources, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to load all sources: %w", err)
+	}
+
+	if len(warnings) > 0 {
+		log.Printf("Encountered %d warning(s) while loading.", len(warnings))
+	}
+
+	findings, err := lint.Check(graph, disabledLint)
 	if err != nil {
-		log.Fatalf("Failed rendering PKI: %v.", err)
+		return fmt.Errorf("failed to lint PKI: %w", err)
+	}
+
+	for _, finding := range findings {
+		log.Printf("Lint: %s", finding)
+	}
+
+	for _, target := range targets {
+		renderTo := func(w io.Writer) error {
+			return pkiplot.Render(ctx, w, graph, append(slices.Clone(opts), pkiplot.WithRenderer(target.renderer))...)
+		}
+
+		if target.filename == "" {
+			err = writeBuffered(os.Stdout, renderTo)
+		} else {
+			err = writeFileAtomic(target.filename, renderTo)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to write %s output: %w", target.format, err)
+		}
 	}
 
-	fmt.Println(rendered)
+	return nil
 }
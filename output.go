@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.xrstf.de/pkiplot/pkg/render"
+)
+
+// outputTarget is a single rendering of the PKI; an empty filename means
+// the output is written to stdout.
+type outputTarget struct {
+	format   string
+	renderer render.Renderer
+	filename string
+}
+
+// parseOutputs turns the --format flags (either "format" or "format=filename")
+// and the --output flag into a list of targets.
+func parseOutputs(formats []string, output string) ([]outputTarget, error) {
+	var (
+		targets []outputTarget
+		stdout  int
+	)
+
+	seenFiles := map[string]bool{}
+
+	for _, format := range formats {
+		name, filename, _ := strings.Cut(format, "=")
+
+		renderer, exists := render.Get(name)
+		if !exists {
+			return nil, fmt.Errorf("invalid output format %q, must be one of %v", name, render.All())
+		}
+
+		if filename == "" {
+			filename = output
+		}
+
+		if filename == "" {
+			stdout++
+		} else {
+			if seenFiles[filename] {
+				return nil, fmt.Errorf("cannot write multiple formats into %s", filename)
+			}
+			seenFiles[filename] = true
+		}
+
+		targets = append(targets, outputTarget{
+			format:   name,
+			renderer: renderer,
+			filename: filename,
+		})
+	}
+
+	if stdout > 1 {
+		return nil, fmt.Errorf("only one format can be written to stdout, use --format=<format>=<filename> for the others")
+	}
+
+	return targets, nil
+}
+
+// writeBuffered wraps the destination in a buffer, as renderers usually
+// perform many small writes. Terminals are written to directly, so that
+// renderers can detect them (e.g. to use colors).
+func writeBuffered(dest io.Writer, write func(w io.Writer) error) error {
+	if render.IsTerminal(dest) {
+		return write(dest)
+	}
+
+	buf := bufio.NewWriter(dest)
+	if err := write(buf); err != nil {
+		return err
+	}
+
+	return buf.Flush()
+}
+
+// writeFileAtomic lets write fill a temporary file in the destination
+// directory and then renames it, so readers never see partial output.
+func writeFileAtomic(filename string, write func(w io.Writer) error) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if err := writeBuffered(tmpFile, write); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	// CreateTemp uses 0600, but the output is not meant to be secret
+	if err := os.Chmod(tmpFile.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), filename)
+}
@@ -0,0 +1,234 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package diagram turns a PKI graph into a laid out scene of boxes and edges,
+// which can then be drawn by the image renderers (SVG, PNG, ...).
+package diagram
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.xrstf.de/pkiplot/pkg/layout"
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	DefaultFontSize = 14
+	TextColor       = "#222"
+
+	// HexagonInset is how far the left and right tips of a hexagon node
+	// stick out from its sides.
+	HexagonInset = 12
+	// RoundedRadius is the corner radius of rounded nodes.
+	RoundedRadius = 6
+
+	paddingX   = 14
+	paddingY   = 8
+	lineHeight = 1.3
+)
+
+// MeasureFunc returns the width of the text when drawn in the given size.
+type MeasureFunc func(text string, fontSize float64) float64
+
+// EstimateWidth approximates the width of text in a proportional sans-serif
+// font, for cases where no font metrics are available.
+func EstimateWidth(text string, fontSize float64) float64 {
+	return float64(len([]rune(text))) * fontSize * 0.6
+}
+
+type Options struct {
+	// ShowType adds the node's type as a second label line.
+	ShowType bool
+	FontSize float64
+	// Measure is used to size the nodes; defaults to EstimateWidth.
+	Measure MeasureFunc
+	Layout  layout.Options
+	// Theme styles nodes and edges; defaults to theme.Default.
+	Theme *theme.Theme
+}
+
+func NewDefaultOptions() Options {
+	return Options{
+		FontSize: DefaultFontSize,
+		Measure:  EstimateWidth,
+		Layout:   layout.NewDefaultOptions(),
+		Theme:    theme.Default,
+	}
+}
+
+type Scene struct {
+	Width    float64
+	Height   float64
+	FontSize float64
+	Nodes    []Node
+	Edges    []Edge
+}
+
+// Node is a box with its top-left corner at X/Y. Colors are in #RRGGBB form.
+type Node struct {
+	ID        string
+	X         float64
+	Y         float64
+	Width     float64
+	Height    float64
+	Lines     []string
+	Class     string
+	Color     string
+	Fill      string
+	Shape     string
+	Dashed    bool
+	Synthetic bool
+}
+
+// LineHeight returns the distance between two label lines.
+func (s *Scene) LineHeight() float64 {
+	return s.FontSize * lineHeight
+}
+
+// Edge points from the issuing/providing node to the dependent node.
+type Edge struct {
+	From   string
+	To     string
+	Points []layout.Point
+	Color  string
+	Dashed bool
+}
+
+// Build lays out the graph with issuers and CAs on top and leaf
+// certificates (and their Secrets) at the bottom, styled with the theme.
+func Build(pki pkigraph.Graph, opt Options) (*Scene, error) {
+	if opt.FontSize <= 0 {
+		opt.FontSize = DefaultFontSize
+	}
+
+	if opt.Measure == nil {
+		opt.Measure = EstimateWidth
+	}
+
+	th := opt.Theme
+	if th == nil {
+		th = theme.Default
+	}
+
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return nil, fmt.Errorf("invalid graph: %w", err)
+	}
+
+	var (
+		nodes       []layout.Node
+		edges       []layout.Edge
+		sceneNodes  = map[string]Node{}
+		lineSpacing = opt.FontSize * lineHeight
+	)
+
+	// sort nodes alphabetically for a stable layout
+	for _, nodeHash := range sets.List(sets.KeySet(amap)) {
+		node, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return nil, fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		lines := slices.Clone(pki.Label(node))
+		if opt.ShowType {
+			lines = append(lines, node.TypeName())
+		}
+
+		width := 0.0
+		for _, line := range lines {
+			width = max(width, opt.Measure(line, opt.FontSize))
+		}
+
+		style := th.NodeStyle(node)
+
+		stroke, fill, err := style.Colors()
+		if err != nil {
+			return nil, err
+		}
+
+		sceneNode := Node{
+			ID:        nodeHash,
+			Width:     width + 2*paddingX,
+			Height:    float64(len(lines))*lineSpacing + 2*paddingY,
+			Lines:     lines,
+			Class:     node.Class(),
+			Color:     theme.Hex(stroke),
+			Fill:      theme.Hex(fill),
+			Shape:     style.Shape,
+			Dashed:    style.Dashed,
+			Synthetic: node.Synthetic,
+		}
+
+		sceneNodes[nodeHash] = sceneNode
+		nodes = append(nodes, layout.Node{
+			ID:     nodeHash,
+			Width:  sceneNode.Width,
+			Height: sceneNode.Height,
+		})
+
+		// reverse the edges, so they point from issuer to issued
+		for _, dest := range sets.List(sets.KeySet(amap[nodeHash])) {
+			edges = append(edges, layout.Edge{From: dest, To: nodeHash})
+		}
+	}
+
+	edgeStyles := map[[2]string]theme.Style{}
+	for _, edge := range edges {
+		dependent, err := pki.Raw().Vertex(edge.To)
+		if err != nil {
+			return nil, fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		dependency, err := pki.Raw().Vertex(edge.From)
+		if err != nil {
+			return nil, fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		edgeStyles[[2]string{edge.From, edge.To}] = th.EdgeStyle(pkigraph.EdgeTypeOf(dependent, dependency))
+	}
+
+	result := layout.Layered(nodes, edges, opt.Layout)
+
+	scene := &Scene{
+		Width:    result.Width,
+		Height:   result.Height,
+		FontSize: opt.FontSize,
+	}
+
+	for _, positioned := range result.Nodes {
+		node := sceneNodes[positioned.ID]
+		node.X = positioned.X
+		node.Y = positioned.Y
+
+		scene.Nodes = append(scene.Nodes, node)
+	}
+
+	for _, routed := range result.Edges {
+		style := edgeStyles[[2]string{routed.From, routed.To}]
+
+		col, _, err := style.Colors()
+		if err != nil {
+			return nil, err
+		}
+
+		scene.Edges = append(scene.Edges, Edge{
+			From:   routed.From,
+			To:     routed.To,
+			Points: routed.Points,
+			Color:  theme.Hex(col),
+			Dashed: style.Dashed,
+		})
+	}
+
+	return scene, nil
+}
+
+// Title returns a tooltip text for a node.
+func (n Node) Title() string {
+	return strings.Join(n.Lines, "\n")
+}
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package label renders node labels from user-provided Go templates, e.g.
+//
+//	{{.Name}}\n{{join .Spec.DNSNames ", "}}
+//
+// A literal "\n" in the output starts a new label line, so templates can be
+// given on the command line without embedding real newlines.
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// DefaultPreset only shows the object's name.
+const DefaultPreset = "name"
+
+// Presets are ready-made templates for the most common use cases. Details
+// that only Certificates have are left out for all other nodes.
+var Presets = map[string]string{
+	"name":       `{{.Name}}`,
+	"type":       `{{.Name}}\n{{.Type}}`,
+	"dnsnames":   `{{.Name}}{{range .Spec.DNSNames}}\n{{.}}{{end}}`,
+	"commonname": `{{.Name}}{{with .Spec.CommonName}}\nCN={{.}}{{end}}`,
+	"duration":   `{{.Name}}{{with .Certificate}}\n{{duration .Spec.Duration}}{{with .Spec.RenewBefore}} (renew {{duration .}} before){{end}}{{end}}`,
+	"algorithm":  `{{.Name}}{{with .Certificate}}\n{{algorithm .}}{{end}}`,
+	"expiry":     `{{.Name}}{{with .Certificate}}{{with .Status.NotAfter}}\nexpires {{date .}}{{end}}{{end}}`,
+}
+
+// PresetNames returns the names of all presets, sorted alphabetically.
+func PresetNames() []string {
+	return sets.List(sets.KeySet(Presets))
+}
+
+// Data is what templates are evaluated against.
+type Data struct {
+	pkigraph.Node
+
+	Name      string
+	Namespace string
+	// Type is the human readable type, e.g. "CA Certificate".
+	Type string
+	// Spec is the Certificate's spec. It is empty for all other nodes, so
+	// templates can use it without checking the node's type first.
+	Spec certmanagerv1.CertificateSpec
+}
+
+type Template struct {
+	tmpl *template.Template
+}
+
+// New parses the given template text.
+func New(text string) (*Template, error) {
+	tmpl, err := template.New("label").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{tmpl: tmpl}, nil
+}
+
+// FromPreset returns the template for the given preset.
+func FromPreset(preset string) (*Template, error) {
+	text, ok := Presets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q, must be one of %v", preset, PresetNames())
+	}
+
+	return New(text)
+}
+
+// Execute renders the label for a node and returns its lines.
+func (t *Template) Execute(node pkigraph.Node) ([]string, error) {
+	data := Data{
+		Node:      node,
+		Name:      node.Name(),
+		Namespace: node.Object().GetNamespace(),
+		Type:      node.TypeName(),
+	}
+
+	if node.Certificate != nil {
+		data.Spec = node.Certificate.Spec
+	}
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	// skip empty lines, e.g. when a field is not set
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(buf.String(), `\n`, "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, nil
+}
+
+var funcs = template.FuncMap{
+	"join":      join,
+	"duration":  duration,
+	"date":      date,
+	"algorithm": algorithm,
+}
+
+// join is like strings.Join, but accepts any slice (or nil).
+func join(list any, sep string) string {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice {
+		return ""
+	}
+
+	items := make([]string, 0, value.Len())
+	for i := range value.Len() {
+		items = append(items, fmt.Sprint(value.Index(i).Interface()))
+	}
+
+	return strings.Join(items, sep)
+}
+
+// duration formats a cert-manager duration, which defaults to 90 days.
+func duration(d any) string {
+	var dur time.Duration
+
+	switch v := d.(type) {
+	case time.Duration:
+		dur = v
+	case metav1.Duration:
+		dur = v.Duration
+	case *metav1.Duration:
+		if v == nil {
+			return "90d (default)"
+		}
+
+		dur = v.Duration
+	default:
+		return fmt.Sprint(d)
+	}
+
+	if dur%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", dur/(24*time.Hour))
+	}
+
+	return dur.String()
+}
+
+// date formats timestamps as YYYY-MM-DD.
+func date(t any) string {
+	switch v := t.(type) {
+	case time.Time:
+		return v.Format(time.DateOnly)
+	case metav1.Time:
+		return v.Format(time.DateOnly)
+	case *metav1.Time:
+		if v == nil {
+			return ""
+		}
+
+		return v.Format(time.DateOnly)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// algorithm returns the private key algorithm and size of a Certificate,
+// e.g. "ECDSA P-256".
+func algorithm(cert *certmanagerv1.Certificate) string {
+	key := cert.Spec.PrivateKey
+	if key == nil || key.Algorithm == "" {
+		return "RSA 2048 (default)"
+	}
+
+	switch key.Algorithm {
+	case certmanagerv1.ECDSAKeyAlgorithm:
+		if key.Size == 0 {
+			return "ECDSA P-256"
+		}
+
+		return fmt.Sprintf("ECDSA P-%d", key.Size)
+	case certmanagerv1.Ed25519KeyAlgorithm:
+		return "Ed25519"
+	default:
+		if key.Size == 0 {
+			return fmt.Sprintf("%s 2048", key.Algorithm)
+		}
+
+		return fmt.Sprintf("%s %d", key.Algorithm, key.Size)
+	}
+}
@@ -0,0 +1,536 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package layout implements a Sugiyama-style layered graph layout: nodes are
+// assigned to layers so that all edges point downwards, the order within
+// each layer is optimized to reduce edge crossings and finally coordinates
+// are assigned so that nodes are close to their neighbours.
+package layout
+
+import (
+	"cmp"
+	"slices"
+)
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+type Node struct {
+	ID     string
+	Width  float64
+	Height float64
+}
+
+type Edge struct {
+	From string
+	To   string
+}
+
+type Options struct {
+	// NodeSpacing is the horizontal gap between two nodes in the same layer.
+	NodeSpacing float64
+	// LayerSpacing is the vertical gap between two layers.
+	LayerSpacing float64
+	// Margin is added around the entire layout.
+	Margin float64
+	// Iterations is the number of sweeps for crossing reduction and
+	// coordinate assignment.
+	Iterations int
+}
+
+func NewDefaultOptions() Options {
+	return Options{
+		NodeSpacing:  30,
+		LayerSpacing: 60,
+		Margin:       20,
+		Iterations:   12,
+	}
+}
+
+// PositionedNode is a node with its top-left corner.
+type PositionedNode struct {
+	Node
+
+	X     float64
+	Y     float64
+	Layer int
+}
+
+// Center returns the center point of the node.
+func (n PositionedNode) Center() Point {
+	return Point{X: n.X + n.Width/2, Y: n.Y + n.Height/2}
+}
+
+// RoutedEdge is an edge with the points it passes through, starting at the
+// From node and ending at the To node.
+type RoutedEdge struct {
+	Edge
+
+	Points []Point
+}
+
+type Layout struct {
+	Width  float64
+	Height float64
+	// Nodes are in the same order as given to Layered.
+	Nodes []PositionedNode
+	// Edges are in the same order as given to Layered, minus all edges that
+	// were ignored (self-loops, duplicates and unknown nodes).
+	Edges []RoutedEdge
+}
+
+// vertex is a node in the layered graph, which also contains dummy vertices
+// for edges spanning more than one layer.
+type vertex struct {
+	node   int // index into the input nodes, -1 for dummies
+	width  float64
+	height float64
+	layer  int
+	pos    int // position within the layer
+	x      float64
+	preds  []int
+	succs  []int
+}
+
+// edgeRef is a valid input edge, referencing nodes by their index.
+type edgeRef struct {
+	edge int
+	from int
+	to   int
+}
+
+// chain is the list of vertices an input edge passes through.
+type chain struct {
+	edge     int
+	vertices []int
+	reversed bool
+}
+
+// Layered computes a top-down layout. Nodes without incoming edges end up
+// in the first layer. Cycles are broken by temporarily reversing edges.
+func Layered(nodes []Node, edges []Edge, opt Options) *Layout {
+	if opt.Iterations < 1 {
+		opt.Iterations = 1
+	}
+
+	indexes := map[string]int{}
+	for i, node := range nodes {
+		indexes[node.ID] = i
+	}
+
+	// collect valid edges and turn them into adjacency lists
+	var valid []edgeRef
+	seen := map[[2]int]bool{}
+	succs := make([][]int, len(nodes))
+
+	for i, edge := range edges {
+		from, ok1 := indexes[edge.From]
+		to, ok2 := indexes[edge.To]
+		if !ok1 || !ok2 || from == to || seen[[2]int{from, to}] {
+			continue
+		}
+
+		seen[[2]int{from, to}] = true
+		valid = append(valid, edgeRef{edge: i, from: from, to: to})
+		succs[from] = append(succs[from], to)
+	}
+
+	reversed := findBackEdges(len(nodes), succs)
+	layers := assignLayers(len(nodes), valid, reversed)
+
+	// build the layered graph, inserting dummy vertices where needed
+	vertices := make([]*vertex, 0, len(nodes))
+	for i, node := range nodes {
+		vertices = append(vertices, &vertex{
+			node:   i,
+			width:  node.Width,
+			height: node.Height,
+			layer:  layers[i],
+		})
+	}
+
+	chains := make([]chain, 0, len(valid))
+	for _, ref := range valid {
+		from, to := ref.from, ref.to
+		isReversed := reversed[[2]int{from, to}]
+		if isReversed {
+			from, to = to, from
+		}
+
+		path := []int{from}
+		for layer := vertices[from].layer + 1; layer < vertices[to].layer; layer++ {
+			vertices = append(vertices, &vertex{node: -1, layer: layer})
+			path = append(path, len(vertices)-1)
+		}
+		path = append(path, to)
+
+		for i := 0; i < len(path)-1; i++ {
+			vertices[path[i]].succs = append(vertices[path[i]].succs, path[i+1])
+			vertices[path[i+1]].preds = append(vertices[path[i+1]].preds, path[i])
+		}
+
+		chains = append(chains, chain{edge: ref.edge, vertices: path, reversed: isReversed})
+	}
+
+	ordering := orderLayers(vertices, opt.Iterations)
+	layerY, layerHeights := assignY(vertices, ordering, opt)
+	assignX(vertices, ordering, opt)
+
+	// normalize so that the leftmost node starts at the margin
+	minX, maxX := 0.0, 0.0
+	for i, v := range vertices {
+		left, right := v.x-v.width/2, v.x+v.width/2
+		if i == 0 || left < minX {
+			minX = left
+		}
+		if i == 0 || right > maxX {
+			maxX = right
+		}
+	}
+
+	shift := opt.Margin - minX
+	for _, v := range vertices {
+		v.x += shift
+	}
+
+	result := &Layout{
+		Width: maxX - minX + 2*opt.Margin,
+	}
+
+	if len(layerY) > 0 {
+		last := len(layerY) - 1
+		result.Height = layerY[last] + layerHeights[last] + opt.Margin
+	} else {
+		result.Height = 2 * opt.Margin
+	}
+
+	for i, node := range nodes {
+		v := vertices[i]
+		result.Nodes = append(result.Nodes, PositionedNode{
+			Node:  node,
+			X:     v.x - v.width/2,
+			Y:     layerY[v.layer] + (layerHeights[v.layer]-v.height)/2,
+			Layer: v.layer,
+		})
+	}
+
+	for _, c := range chains {
+		points := make([]Point, 0, len(c.vertices)+2)
+
+		first := result.Nodes[c.vertices[0]]
+		points = append(points, Point{X: first.X + first.Width/2, Y: first.Y + first.Height})
+
+		for _, idx := range c.vertices[1 : len(c.vertices)-1] {
+			v := vertices[idx]
+			top := layerY[v.layer]
+			points = append(points, Point{X: v.x, Y: top}, Point{X: v.x, Y: top + layerHeights[v.layer]})
+		}
+
+		last := result.Nodes[c.vertices[len(c.vertices)-1]]
+		points = append(points, Point{X: last.X + last.Width/2, Y: last.Y})
+
+		if c.reversed {
+			slices.Reverse(points)
+		}
+
+		result.Edges = append(result.Edges, RoutedEdge{Edge: edges[c.edge], Points: points})
+	}
+
+	return result
+}
+
+// findBackEdges returns all edges that close a cycle when doing a depth-first
+// search in node order; reversing them makes the graph acyclic.
+func findBackEdges(n int, succs [][]int) map[[2]int]bool {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+
+	state := make([]int, n)
+	back := map[[2]int]bool{}
+
+	var visit func(v int)
+	visit = func(v int) {
+		state[v] = active
+		for _, w := range succs[v] {
+			switch state[w] {
+			case unvisited:
+				visit(w)
+			case active:
+				back[[2]int{v, w}] = true
+			}
+		}
+		state[v] = done
+	}
+
+	for v := range n {
+		if state[v] == unvisited {
+			visit(v)
+		}
+	}
+
+	return back
+}
+
+// assignLayers uses the longest path from any source, so that every edge
+// points at least one layer downwards.
+func assignLayers(n int, edges []edgeRef, reversed map[[2]int]bool) []int {
+	succs := make([][]int, n)
+	indegree := make([]int, n)
+
+	for _, ref := range edges {
+		from, to := ref.from, ref.to
+		if reversed[[2]int{from, to}] {
+			from, to = to, from
+		}
+
+		succs[from] = append(succs[from], to)
+		indegree[to]++
+	}
+
+	layers := make([]int, n)
+	queue := []int{}
+	for v := range n {
+		if indegree[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+
+		for _, w := range succs[v] {
+			layers[w] = max(layers[w], layers[v]+1)
+
+			indegree[w]--
+			if indegree[w] == 0 {
+				queue = append(queue, w)
+			}
+		}
+	}
+
+	return layers
+}
+
+// orderLayers sorts the vertices within each layer using the barycenter
+// heuristic and returns the ordering with the fewest crossings.
+func orderLayers(vertices []*vertex, iterations int) [][]int {
+	numLayers := 0
+	for _, v := range vertices {
+		numLayers = max(numLayers, v.layer+1)
+	}
+
+	ordering := make([][]int, numLayers)
+	for i, v := range vertices {
+		ordering[v.layer] = append(ordering[v.layer], i)
+	}
+
+	updatePositions := func(order [][]int) {
+		for _, layer := range order {
+			for pos, idx := range layer {
+				vertices[idx].pos = pos
+			}
+		}
+	}
+
+	updatePositions(ordering)
+
+	best := cloneOrdering(ordering)
+	bestCrossings := countCrossings(vertices, ordering)
+
+	for i := 0; i < iterations && bestCrossings > 0; i++ {
+		if i%2 == 0 {
+			for layer := 1; layer < numLayers; layer++ {
+				sortByBarycenter(vertices, ordering[layer], func(v *vertex) []int { return v.preds })
+				updatePositions(ordering[layer : layer+1])
+			}
+		} else {
+			for layer := numLayers - 2; layer >= 0; layer-- {
+				sortByBarycenter(vertices, ordering[layer], func(v *vertex) []int { return v.succs })
+				updatePositions(ordering[layer : layer+1])
+			}
+		}
+
+		if crossings := countCrossings(vertices, ordering); crossings < bestCrossings {
+			best = cloneOrdering(ordering)
+			bestCrossings = crossings
+		}
+	}
+
+	updatePositions(best)
+
+	return best
+}
+
+func cloneOrdering(ordering [][]int) [][]int {
+	result := make([][]int, len(ordering))
+	for i, layer := range ordering {
+		result[i] = slices.Clone(layer)
+	}
+
+	return result
+}
+
+func sortByBarycenter(vertices []*vertex, layer []int, neighbours func(*vertex) []int) {
+	barycenters := map[int]float64{}
+
+	for _, idx := range layer {
+		v := vertices[idx]
+		adjacent := neighbours(v)
+
+		// vertices without neighbours keep their position
+		if len(adjacent) == 0 {
+			barycenters[idx] = float64(v.pos)
+			continue
+		}
+
+		sum := 0.0
+		for _, n := range adjacent {
+			sum += float64(vertices[n].pos)
+		}
+
+		barycenters[idx] = sum / float64(len(adjacent))
+	}
+
+	slices.SortStableFunc(layer, func(a, b int) int {
+		return cmp.Compare(barycenters[a], barycenters[b])
+	})
+}
+
+// countCrossings counts the edge crossings between all adjacent layers,
+// using a Fenwick tree to count inversions in O(E log V).
+func countCrossings(vertices []*vertex, ordering [][]int) int {
+	total := 0
+
+	for layer := 0; layer < len(ordering)-1; layer++ {
+		var pairs [][2]int
+		for _, idx := range ordering[layer] {
+			for _, succ := range vertices[idx].succs {
+				pairs = append(pairs, [2]int{vertices[idx].pos, vertices[succ].pos})
+			}
+		}
+
+		slices.SortFunc(pairs, func(a, b [2]int) int {
+			return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+		})
+
+		tree := make([]int, len(ordering[layer+1])+1)
+		for i, pair := range pairs {
+			// count previously inserted edges that end further right
+			lessOrEqual := 0
+			for j := pair[1] + 1; j > 0; j -= j & -j {
+				lessOrEqual += tree[j]
+			}
+			total += i - lessOrEqual
+
+			for j := pair[1] + 1; j < len(tree); j += j & -j {
+				tree[j]++
+			}
+		}
+	}
+
+	return total
+}
+
+func assignY(vertices []*vertex, ordering [][]int, opt Options) ([]float64, []float64) {
+	heights := make([]float64, len(ordering))
+	for _, v := range vertices {
+		heights[v.layer] = max(heights[v.layer], v.height)
+	}
+
+	ys := make([]float64, len(ordering))
+	y := opt.Margin
+	for layer := range ordering {
+		ys[layer] = y
+		y += heights[layer] + opt.LayerSpacing
+	}
+
+	return ys, heights
+}
+
+// assignX places all vertices as close to their neighbours as possible
+// while keeping the order and minimum distance within each layer.
+func assignX(vertices []*vertex, ordering [][]int, opt Options) {
+	separation := func(a, b *vertex) float64 {
+		gap := opt.NodeSpacing
+		if a.node < 0 || b.node < 0 {
+			gap /= 2
+		}
+
+		return (a.width+b.width)/2 + gap
+	}
+
+	// start with all layers packed to the left
+	for _, layer := range ordering {
+		x := 0.0
+		for i, idx := range layer {
+			if i > 0 {
+				x += separation(vertices[layer[i-1]], vertices[idx])
+			}
+			vertices[idx].x = x
+		}
+	}
+
+	place := func(layer []int, neighbours func(*vertex) []int) {
+		desired := make([]float64, len(layer))
+		for i, idx := range layer {
+			v := vertices[idx]
+			adjacent := neighbours(v)
+			if len(adjacent) == 0 {
+				desired[i] = v.x
+				continue
+			}
+
+			sum := 0.0
+			for _, n := range adjacent {
+				sum += vertices[n].x
+			}
+			desired[i] = sum / float64(len(adjacent))
+		}
+
+		// Pack once from the left and once from the right, each time as
+		// close to the desired positions as possible; the average of both
+		// still satisfies the separation constraints.
+		left := make([]float64, len(layer))
+		for i, idx := range layer {
+			left[i] = desired[i]
+			if i > 0 {
+				left[i] = max(left[i], left[i-1]+separation(vertices[layer[i-1]], vertices[idx]))
+			}
+		}
+
+		right := make([]float64, len(layer))
+		for i := len(layer) - 1; i >= 0; i-- {
+			right[i] = desired[i]
+			if i < len(layer)-1 {
+				right[i] = min(right[i], right[i+1]-separation(vertices[layer[i]], vertices[layer[i+1]]))
+			}
+		}
+
+		for i, idx := range layer {
+			vertices[idx].x = (left[i] + right[i]) / 2
+		}
+	}
+
+	for i := range opt.Iterations {
+		if i%2 == 0 {
+			for layer := 1; layer < len(ordering); layer++ {
+				place(ordering[layer], func(v *vertex) []int { return v.preds })
+			}
+		} else {
+			for layer := len(ordering) - 2; layer >= 0; layer-- {
+				place(ordering[layer], func(v *vertex) []int { return v.succs })
+			}
+		}
+	}
+
+	// a final pass to center parents above their children and vice versa
+	for layer := range ordering {
+		place(ordering[layer], func(v *vertex) []int { return append(slices.Clone(v.preds), v.succs...) })
+	}
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package lint checks a PKI graph for common mistakes. As the graph only
+// contains what was loaded, the rules can only point out inconsistencies
+// within the given manifests.
+package lint
+
+import (
+	"fmt"
+
+	"github.com/dominikbraun/graph"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Finding is a potential problem in the PKI.
+type Finding struct {
+	// Rule is the name of the rule that reported the finding.
+	Rule    string
+	Node    pkigraph.Node
+	Message string
+}
+
+// String returns the finding in a single line, e.g. for log output.
+func (f Finding) String() string {
+	name := f.Node.Name()
+	if ns := f.Node.Object().GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+
+	return fmt.Sprintf("%s %s: %s (%s)", f.Node.Kind(), name, f.Message, f.Rule)
+}
+
+type Rule struct {
+	Name        string
+	Description string
+
+	check func(c *checker, node pkigraph.Node) []string
+}
+
+// RuleNames returns the names of all rules, in the order they are applied.
+func RuleNames() []string {
+	names := make([]string, 0, len(Rules))
+	for _, rule := range Rules {
+		names = append(names, rule.Name)
+	}
+
+	return names
+}
+
+// ValidateRuleNames returns an error if any of the names is not a known rule.
+func ValidateRuleNames(names []string) error {
+	known := sets.New(RuleNames()...)
+
+	for _, name := range names {
+		if !known.Has(name) {
+			return fmt.Errorf("unknown lint rule %q, must be one of %v", name, RuleNames())
+		}
+	}
+
+	return nil
+}
+
+type checker struct {
+	pki   pkigraph.Graph
+	amap  map[string]map[string]graph.Edge[string]
+	nodes []pkigraph.Node
+
+	// secretWriters are the names of all Certificates per Secret
+	// (namespace/name).
+	secretWriters map[string][]string
+}
+
+// Check applies all rules that are not disabled to every node. Findings are
+// sorted by node and then by rule.
+func Check(pki pkigraph.Graph, disabled []string) ([]Finding, error) {
+	if err := ValidateRuleNames(disabled); err != nil {
+		return nil, err
+	}
+
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return nil, fmt.Errorf("invalid graph: %w", err)
+	}
+
+	c := &checker{
+		pki:           pki,
+		amap:          amap,
+		secretWriters: map[string][]string{},
+	}
+
+	for _, nodeHash := range sets.List(sets.KeySet(amap)) {
+		node, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return nil, fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		c.nodes = append(c.nodes, node)
+
+		if cert := node.Certificate; cert != nil && !node.Synthetic && cert.Spec.SecretName != "" {
+			key := cert.Namespace + "/" + cert.Spec.SecretName
+			c.secretWriters[key] = append(c.secretWriters[key], node.Name())
+		}
+	}
+
+	skip := sets.New(disabled...)
+
+	var findings []Finding
+	for _, node := range c.nodes {
+		for _, rule := range Rules {
+			if skip.Has(rule.Name) {
+				continue
+			}
+
+			for _, message := range rule.check(c, node) {
+				findings = append(findings, Finding{
+					Rule:    rule.Name,
+					Node:    node,
+					Message: message,
+				})
+			}
+		}
+	}
+
+	return findings, nil
+}
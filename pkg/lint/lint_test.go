@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package lint
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.xrstf.de/pkiplot/pkg/loader"
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+)
+
+const selfSignedIssuer = `
+apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: selfsigned
+  namespace: default
+spec:
+  selfSigned: {}
+`
+
+func certificate(name string, spec string) string {
+	return `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: ` + name + `
+  namespace: default
+spec:
+` + spec
+}
+
+func caIssuer(name string, secretName string) string {
+	return `
+apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: ` + name + `
+  namespace: default
+spec:
+  ca:
+    secretName: ` + secretName + `
+`
+}
+
+func documents(docs ...string) string {
+	return strings.Join(docs, "---")
+}
+
+func TestCheck(t *testing.T) {
+	testcases := []struct {
+		name       string
+		manifests  string
+		synthetics bool
+		disabled   []string
+		expected   []string
+		invalid    bool
+	}{
+		{
+			name: "valid PKI",
+			manifests: documents(
+				selfSignedIssuer,
+				certificate("root", "  isCA: true\n  secretName: root\n  issuerRef: {name: selfsigned}\n"),
+				caIssuer("root", "root"),
+				certificate("web", "  secretName: web\n  duration: 2h\n  renewBefore: 1h\n  issuerRef: {name: root}\n"),
+			),
+			expected: nil,
+		},
+		{
+			name:       "missing-object",
+			manifests:  certificate("web", "  secretName: web\n  issuerRef: {name: missing}\n"),
+			synthetics: true,
+			expected: []string{
+				"Issuer default/missing: Issuer is referenced, but was not found in the manifests. (missing-object)",
+			},
+		},
+		{
+			name:      "missing-issuer",
+			manifests: certificate("web", "  secretName: web\n  issuerRef: {name: missing, kind: Issuer}\n"),
+			expected: []string{
+				"Certificate default/web: The referenced Issuer `missing` is not part of the manifests. (missing-issuer)",
+			},
+		},
+		{
+			name: "missing-secret-name",
+			manifests: documents(
+				selfSignedIssuer,
+				certificate("web", "  issuerRef: {name: selfsigned}\n"),
+			),
+			expected: []string{
+				"Certificate default/web: No secretName is configured. (missing-secret-name)",
+			},
+		},
+		{
+			name: "renew-before",
+			manifests: documents(
+				selfSignedIssuer,
+				certificate("web", "  secretName: web\n  duration: 1h\n  renewBefore: 2h\n  issuerRef: {name: selfsigned}\n"),
+			),
+			expected: []string{
+				"Certificate default/web: renewBefore (2h0m0s) is not shorter than the duration (1h0m0s). (renew-before)",
+			},
+		},
+		{
+			name: "shared-secret",
+			manifests: documents(
+				selfSignedIssuer,
+				certificate("a", "  secretName: shared\n  issuerRef: {name: selfsigned}\n"),
+				certificate("b", "  secretName: shared\n  issuerRef: {name: selfsigned}\n"),
+			),
+			expected: []string{
+				"Certificate default/a: Secret `shared` is also written by b. (shared-secret)",
+				"Certificate default/b: Secret `shared` is also written by a. (shared-secret)",
+			},
+		},
+		{
+			name:      "ca-secret without Certificate",
+			manifests: caIssuer("root", "root"),
+			expected: []string{
+				"Issuer default/root: The CA Secret is neither part of the manifests nor created by any Certificate. (ca-secret)",
+			},
+		},
+		{
+			name: "ca-secret created by a non-CA Certificate",
+			manifests: documents(
+				selfSignedIssuer,
+				certificate("root", "  secretName: root\n  issuerRef: {name: selfsigned}\n"),
+				caIssuer("root", "root"),
+			),
+			expected: []string{
+				"Issuer default/root: The CA Secret is created by Certificate root, which is not a CA. (ca-secret)",
+			},
+		},
+		{
+			name: "disabled rule",
+			manifests: documents(
+				selfSignedIssuer,
+				certificate("a", "  secretName: shared\n  duration: 1h\n  renewBefore: 2h\n  issuerRef: {name: selfsigned}\n"),
+				certificate("b", "  secretName: shared\n  issuerRef: {name: selfsigned}\n"),
+			),
+			disabled: []string{"shared-secret"},
+			expected: []string{
+				"Certificate default/a: renewBefore (2h0m0s) is not shorter than the duration (1h0m0s). (renew-before)",
+			},
+		},
+		{
+			name:      "unknown disabled rule",
+			manifests: selfSignedIssuer,
+			disabled:  []string{"does-not-exist"},
+			invalid:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pki, _, err := loader.LoadSources(context.Background(), []loader.Source{loader.FromReader("test", strings.NewReader(tc.manifests))}, nil)
+			if err != nil {
+				t.Fatalf("Failed to load PKI: %v", err)
+			}
+
+			graph := pkigraph.NewFromPKI(pki, pkigraph.Options{ShowSynthetics: tc.synthetics})
+
+			findings, err := Check(graph, tc.disabled)
+			if tc.invalid {
+				if err == nil {
+					t.Fatal("Expected an error, but got none.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Failed to check PKI: %v", err)
+			}
+
+			var results []string
+			for _, finding := range findings {
+				results = append(results, finding.String())
+			}
+
+			if !slices.Equal(results, tc.expected) {
+				t.Fatalf("Expected findings\n%s\n\ngot\n%s", strings.Join(tc.expected, "\n"), strings.Join(results, "\n"))
+			}
+		})
+	}
+}
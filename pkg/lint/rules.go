@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+)
+
+// Rules are all available rules, in the order they are applied to each node.
+var Rules = []Rule{
+	{
+		Name:        "missing-object",
+		Description: "Issuers and Certificates that are referenced must be part of the manifests.",
+		check:       checkMissingObject,
+	},
+	{
+		Name:        "missing-issuer",
+		Description: "The issuer of a Certificate must be part of the manifests.",
+		check:       checkMissingIssuer,
+	},
+	{
+		Name:        "missing-secret-name",
+		Description: "Certificates must configure a secretName.",
+		check:       checkMissingSecretName,
+	},
+	{
+		Name:        "renew-before",
+		Description: "A Certificate's renewBefore must be shorter than its duration.",
+		check:       checkRenewBefore,
+	},
+	{
+		Name:        "shared-secret",
+		Description: "Certificates must not write into the same Secret.",
+		check:       checkSharedSecret,
+	},
+	{
+		Name:        "ca-secret",
+		Description: "The Secret of a CA issuer must be part of the manifests or created by a CA Certificate.",
+		check:       checkCASecret,
+	},
+}
+
+func checkMissingObject(c *checker, node pkigraph.Node) []string {
+	// missing Secrets are normal, as they are created at runtime
+	if !node.Synthetic || node.Secret != nil {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%s is referenced, but was not found in the manifests.", node.Kind())}
+}
+
+func checkMissingIssuer(c *checker, node pkigraph.Node) []string {
+	if node.Certificate == nil || node.Synthetic {
+		return nil
+	}
+
+	for dependency := range c.amap[node.Hash()] {
+		if dep, err := c.pki.Raw().Vertex(dependency); err == nil && pkigraph.EdgeTypeOf(node, dep) == pkigraph.EdgeIssues {
+			return nil
+		}
+	}
+
+	return []string{fmt.Sprintf("The referenced %s is not part of the manifests.", issuerRef(node))}
+}
+
+func checkMissingSecretName(c *checker, node pkigraph.Node) []string {
+	if node.Certificate == nil || node.Synthetic || node.Certificate.Spec.SecretName != "" {
+		return nil
+	}
+
+	return []string{"No secretName is configured."}
+}
+
+func checkRenewBefore(c *checker, node pkigraph.Node) []string {
+	if node.Certificate == nil || node.Synthetic {
+		return nil
+	}
+
+	spec := node.Certificate.Spec
+	if spec.Duration == nil || spec.RenewBefore == nil || spec.RenewBefore.Duration < spec.Duration.Duration {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("renewBefore (%s) is not shorter than the duration (%s).", spec.RenewBefore.Duration, spec.Duration.Duration)}
+}
+
+func checkSharedSecret(c *checker, node pkigraph.Node) []string {
+	cert := node.Certificate
+	if cert == nil || node.Synthetic || cert.Spec.SecretName == "" {
+		return nil
+	}
+
+	others := slices.DeleteFunc(slices.Clone(c.secretWriters[cert.Namespace+"/"+cert.Spec.SecretName]), func(name string) bool {
+		return name == node.Name()
+	})
+
+	if len(others) == 0 {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("Secret `%s` is also written by %s.", cert.Spec.SecretName, strings.Join(others, ", "))}
+}
+
+func checkCASecret(c *checker, node pkigraph.Node) []string {
+	if node.Synthetic || node.IssuerType() != "ca" {
+		return nil
+	}
+
+	// CA issuers depend on their Secret, or directly on the Certificates
+	// creating that Secret if Secrets are not shown.
+	var (
+		sources []pkigraph.Node
+		pending = []string{node.Hash()}
+	)
+
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		for dependency := range c.amap[current] {
+			dep, err := c.pki.Raw().Vertex(dependency)
+			if err != nil {
+				continue
+			}
+
+			switch {
+			case dep.Secret != nil:
+				if !dep.Synthetic {
+					sources = append(sources, dep)
+				}
+				pending = append(pending, dependency)
+			case dep.Certificate != nil:
+				sources = append(sources, dep)
+			}
+		}
+	}
+
+	// Missing Secrets are not part of the graph if synthetics are hidden, so
+	// fall back to matching Certificates by their secretName. The namespace
+	// for ClusterIssuers is not known here, so any namespace matches.
+	if len(sources) == 0 {
+		sources = c.secretCreators(node)
+	}
+
+	if len(sources) == 0 {
+		return []string{"The CA Secret is neither part of the manifests nor created by any Certificate."}
+	}
+
+	var messages []string
+	for _, source := range sources {
+		if source.Certificate != nil && !source.IsCA() {
+			messages = append(messages, fmt.Sprintf("The CA Secret is created by Certificate %s, which is not a CA.", source.Name()))
+		}
+	}
+
+	return messages
+}
+
+func (c *checker) secretCreators(issuer pkigraph.Node) []pkigraph.Node {
+	var (
+		secretName string
+		namespace  string
+	)
+
+	if issuer.Issuer != nil {
+		secretName = issuer.Issuer.Spec.CA.SecretName
+		namespace = issuer.Issuer.Namespace
+	} else {
+		secretName = issuer.ClusterIssuer.Spec.CA.SecretName
+	}
+
+	var creators []pkigraph.Node
+	for _, node := range c.nodes {
+		cert := node.Certificate
+		if cert == nil || node.Synthetic || cert.Spec.SecretName != secretName {
+			continue
+		}
+
+		if namespace == "" || cert.Namespace == namespace {
+			creators = append(creators, node)
+		}
+	}
+
+	return creators
+}
+
+// issuerRef describes the issuer of a Certificate node, e.g. "Issuer `foo`".
+func issuerRef(node pkigraph.Node) string {
+	ref := node.Certificate.Spec.IssuerRef
+
+	kind := ref.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+
+	return fmt.Sprintf("%s `%s`", kind, ref.Name)
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const legacyGroup = "certmanager.k8s.io"
+
+// legacyVersions are all historical cert-manager API versions that can be
+// converted into cert-manager.io/v1.
+var legacyVersions = sets.New(
+	schema.GroupVersion{Group: legacyGroup, Version: "v1alpha1"},
+	schema.GroupVersion{Group: certmanagerv1.SchemeGroupVersion.Group, Version: "v1alpha2"},
+	schema.GroupVersion{Group: certmanagerv1.SchemeGroupVersion.Group, Version: "v1alpha3"},
+	schema.GroupVersion{Group: certmanagerv1.SchemeGroupVersion.Group, Version: "v1beta1"},
+)
+
+// convertLegacyObject converts a Certificate, Issuer or ClusterIssuer from a
+// historical API version in-place to cert-manager.io/v1. The returned strings
+// describe all information that could not be converted and was dropped.
+func convertLegacyObject(obj *unstructured.Unstructured) []string {
+	gvk := obj.GroupVersionKind()
+
+	var lost []string
+	switch gvk.Kind {
+	case "Certificate":
+		lost = convertLegacyCertificate(obj.Object, gvk.GroupVersion())
+	case "Issuer", "ClusterIssuer":
+		lost = convertLegacyIssuer(obj.Object)
+	}
+
+	obj.SetAPIVersion(certmanagerv1.SchemeGroupVersion.String())
+
+	return lost
+}
+
+func convertLegacyCertificate(obj map[string]any, gv schema.GroupVersion) []string {
+	var lost []string
+
+	// v1beta1 already has the v1 structure for private keys and SANs
+	if gv.Version != "v1beta1" {
+		moveField(obj, []string{"spec", "keyAlgorithm"}, []string{"spec", "privateKey", "algorithm"}, normalizeKeyAlgorithm)
+		moveField(obj, []string{"spec", "keySize"}, []string{"spec", "privateKey", "size"}, nil)
+		moveField(obj, []string{"spec", "keyEncoding"}, []string{"spec", "privateKey", "encoding"}, strings.ToUpper)
+		moveField(obj, []string{"spec", "uriSANs"}, []string{"spec", "uris"}, nil)
+		moveField(obj, []string{"spec", "emailSANs"}, []string{"spec", "emailAddresses"}, nil)
+
+		if orgs, found, _ := unstructured.NestedStringSlice(obj, "spec", "organization"); found {
+			existing, _, _ := unstructured.NestedStringSlice(obj, "spec", "subject", "organizations")
+			_ = unstructured.SetNestedStringSlice(obj, append(existing, orgs...), "spec", "subject", "organizations")
+			unstructured.RemoveNestedField(obj, "spec", "organization")
+		}
+	}
+
+	// certmanager.k8s.io allowed to configure ACME challenges on the Certificate
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj, "spec", "acme"); found {
+		unstructured.RemoveNestedField(obj, "spec", "acme")
+		lost = append(lost, "spec.acme (ACME challenges are configured on the Issuer since cert-manager.io/v1alpha2)")
+	}
+
+	if group, _, _ := unstructured.NestedString(obj, "spec", "issuerRef", "group"); group == legacyGroup {
+		_ = unstructured.SetNestedField(obj, certmanagerv1.SchemeGroupVersion.Group, "spec", "issuerRef", "group")
+	}
+
+	return lost
+}
+
+func convertLegacyIssuer(obj map[string]any) []string {
+	var lost []string
+
+	// solvers used to be configured in a completely different structure
+	for _, field := range []string{"http01", "dns01"} {
+		if _, found, _ := unstructured.NestedFieldNoCopy(obj, "spec", "acme", field); found {
+			unstructured.RemoveNestedField(obj, "spec", "acme", field)
+			lost = append(lost, fmt.Sprintf("spec.acme.%s (legacy ACME solver configuration)", field))
+		}
+	}
+
+	return lost
+}
+
+// moveField moves a value from one path to another, unless the destination
+// is already set. String values can optionally be transformed.
+func moveField(obj map[string]any, from, to []string, transform func(string) string) {
+	value, found, _ := unstructured.NestedFieldCopy(obj, from...)
+	if !found {
+		return
+	}
+
+	unstructured.RemoveNestedField(obj, from...)
+
+	if _, exists, _ := unstructured.NestedFieldNoCopy(obj, to...); exists {
+		return
+	}
+
+	if s, ok := value.(string); ok && transform != nil {
+		value = transform(s)
+	}
+
+	_ = unstructured.SetNestedField(obj, value, to...)
+}
+
+func normalizeKeyAlgorithm(algorithm string) string {
+	switch strings.ToLower(algorithm) {
+	case "rsa":
+		return string(certmanagerv1.RSAKeyAlgorithm)
+	case "ecdsa":
+		return string(certmanagerv1.ECDSAKeyAlgorithm)
+	case "ed25519":
+		return string(certmanagerv1.Ed25519KeyAlgorithm)
+	default:
+		return algorithm
+	}
+}
@@ -5,13 +5,19 @@ package loader
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	goruntime "runtime"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 
@@ -20,123 +26,261 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
 )
 
 type Options struct {
-	Namespace      string
+	// DefaultNamespace is assigned to namespace-scoped objects that do not
+	// have a namespace set.
+	DefaultNamespace string
+	// IncludeNamespaces is a list of glob patterns (see path.Match); if given,
+	// only namespace-scoped objects in matching namespaces are loaded.
+	IncludeNamespaces []string
+	// ExcludeNamespaces is a list of glob patterns; namespace-scoped objects
+	// in matching namespaces are skipped. Exclusions win over inclusions.
+	ExcludeNamespaces []string
+	// LabelSelector, if set, is applied to all loaded objects.
+	LabelSelector  labels.Selector
 	FileExtensions []string
+	// Workers is the number of files that are loaded concurrently when
+	// reading directories.
+	Workers int
+	// MaxDocumentSize is the maximum size of a single YAML document in bytes;
+	// 0 means no limit.
+	MaxDocumentSize int
+	// Lenient makes the loader skip invalid documents and objects instead of
+	// failing; each skipped item is reported as a Warning.
+	Lenient bool
+}
+
+var (
+	// ErrInvalidDocument is returned for documents that cannot be parsed.
+	ErrInvalidDocument = errors.New("document is not valid Kubernetes YAML")
+	// ErrInvalidObject is returned for objects that do not match the schema
+	// of their kind.
+	ErrInvalidObject = errors.New("object does not match its schema")
+	// ErrUnknownAPIVersion is returned for Certificates, Issuers,
+	// ClusterIssuers and Secrets with an unsupported apiVersion.
+	ErrUnknownAPIVersion = errors.New("unknown apiVersion")
+	// ErrMissingNamespace is returned for namespace-scoped objects without a
+	// namespace if no default namespace is configured.
+	ErrMissingNamespace = errors.New("no metadata.namespace set")
+)
+
+// Warning describes a problem encountered while loading, like data that was
+// lost when converting legacy objects or, in lenient mode, documents and
+// objects that were skipped. For skipped documents and objects, Err wraps
+// ErrInvalidDocument, ErrDocumentTooLarge, ErrInvalidObject,
+// ErrUnknownAPIVersion or ErrMissingNamespace.
+type Warning struct {
+	Source   string
+	Document int
+	Err      error
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: document %d: %v", w.Source, w.Document, w.Err)
+}
+
+// loadResult is the PKI that is being assembled while loading sources,
+// plus all warnings encountered along the way.
+type loadResult struct {
+	*types.PKI
+
+	warnings []Warning
+}
+
+func newLoadResult() *loadResult {
+	return &loadResult{
+		PKI: &types.PKI{
+			Secrets:        []corev1.Secret{},
+			Certificates:   []certmanagerv1.Certificate{},
+			Issuers:        []certmanagerv1.Issuer{},
+			ClusterIssuers: []certmanagerv1.ClusterIssuer{},
+		},
+	}
+}
+
+func (r *loadResult) add(pki *types.PKI) {
+	r.Secrets = append(r.Secrets, pki.Secrets...)
+	r.Certificates = append(r.Certificates, pki.Certificates...)
+	r.Issuers = append(r.Issuers, pki.Issuers...)
+	r.ClusterIssuers = append(r.ClusterIssuers, pki.ClusterIssuers...)
+}
+
+func (r *loadResult) merge(other *loadResult) {
+	r.add(other.PKI)
+	r.warnings = append(r.warnings, other.warnings...)
+}
+
+func (r *loadResult) warn(source string, document int, err error) {
+	r.warnings = append(r.warnings, Warning{
+		Source:   source,
+		Document: document,
+		Err:      err,
+	})
 }
 
 func NewDefaultOptions() *Options {
 	return &Options{
 		FileExtensions: []string{"yaml", "yml"},
+		Workers:        goruntime.GOMAXPROCS(0),
 	}
 }
 
-func LoadPKI(sources []string, opt *Options) (*types.PKI, error) {
+// LoadPKI loads all files, directories or stdin ("-").
+func LoadPKI(ctx context.Context, sources []string, opt *Options) (*types.PKI, []Warning, error) {
+	converted := make([]Source, 0, len(sources))
+	for _, source := range sources {
+		converted = append(converted, FromPath(source))
+	}
+
+	return LoadSources(ctx, converted, opt)
+}
+
+// LoadSources loads all given sources. Cancelling the context stops loading
+// between documents and files.
+func LoadSources(ctx context.Context, sources []Source, opt *Options) (*types.PKI, []Warning, error) {
 	if len(sources) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	if opt == nil {
 		opt = NewDefaultOptions()
 	}
 
-	result := &types.PKI{
-		Secrets:        []corev1.Secret{},
-		Certificates:   []certmanagerv1.Certificate{},
-		Issuers:        []certmanagerv1.Issuer{},
-		ClusterIssuers: []certmanagerv1.ClusterIssuer{},
+	if err := validateGlobs(opt.IncludeNamespaces); err != nil {
+		return nil, nil, fmt.Errorf("invalid namespace inclusion: %w", err)
+	}
+
+	if err := validateGlobs(opt.ExcludeNamespaces); err != nil {
+		return nil, nil, fmt.Errorf("invalid namespace exclusion: %w", err)
 	}
 
+	loaded := newLoadResult()
+
 	// load from all sources
 
 	for _, source := range sources {
-		if err := loadManifestsSource(result, opt, source); err != nil {
-			return nil, fmt.Errorf("failed to load from %q: %w", source, err)
+		if err := loadSource(ctx, loaded, opt, source); err != nil {
+			return nil, nil, fmt.Errorf("failed to load from %q: %w", source, err)
 		}
 	}
 
-	// forbid duplicates
+	result := loaded.PKI
+
+	// forbid duplicates; objects that only have a generateName are told apart
+	// by the order they were loaded in, see sort.SliceStable below
 
 	identifiers := sets.New[string]()
 	for idx, cert := range result.Certificates {
 		ident, err := getResourceIdentifier(&cert)
 		if err != nil {
-			return nil, fmt.Errorf("Certificate %d is invalid: %w", idx, err)
+			return nil, nil, fmt.Errorf("Certificate %d is invalid: %w", idx, err)
+		}
+
+		if ident == "" {
+			continue
 		}
 
 		if identifiers.Has(ident) {
-			return nil, fmt.Errorf("found multiple definitions for Certificate %s", ident)
+			return nil, nil, fmt.Errorf("found multiple definitions for Certificate %s", ident)
 		}
+
+		identifiers.Insert(ident)
 	}
 
 	identifiers = sets.New[string]()
 	for idx, secret := range result.Secrets {
 		ident, err := getResourceIdentifier(&secret)
 		if err != nil {
-			return nil, fmt.Errorf("Secret %d is invalid: %w", idx, err)
+			return nil, nil, fmt.Errorf("Secret %d is invalid: %w", idx, err)
+		}
+
+		if ident == "" {
+			continue
 		}
 
 		if identifiers.Has(ident) {
-			return nil, fmt.Errorf("found multiple definitions for Secret %s", ident)
+			return nil, nil, fmt.Errorf("found multiple definitions for Secret %s", ident)
 		}
+
+		identifiers.Insert(ident)
 	}
 
 	identifiers = identifiers.Clear()
 	for idx, issuer := range result.Issuers {
 		ident, err := getResourceIdentifier(&issuer)
 		if err != nil {
-			return nil, fmt.Errorf("Issuer %d is invalid: %w", idx, err)
+			return nil, nil, fmt.Errorf("Issuer %d is invalid: %w", idx, err)
+		}
+
+		if ident == "" {
+			continue
 		}
 
 		if identifiers.Has(ident) {
-			return nil, fmt.Errorf("found multiple definitions for Issuer %s", ident)
+			return nil, nil, fmt.Errorf("found multiple definitions for Issuer %s", ident)
 		}
+
+		identifiers.Insert(ident)
 	}
 
 	identifiers = identifiers.Clear()
 	for idx, clusterIssuer := range result.ClusterIssuers {
 		ident, err := getResourceIdentifier(&clusterIssuer)
 		if err != nil {
-			return nil, fmt.Errorf("ClusterIssuer %d is invalid: %w", idx, err)
+			return nil, nil, fmt.Errorf("ClusterIssuer %d is invalid: %w", idx, err)
+		}
+
+		if ident == "" {
+			continue
 		}
 
 		if identifiers.Has(ident) {
-			return nil, fmt.Errorf("found multiple definitions for ClusterIssuer %s", ident)
+			return nil, nil, fmt.Errorf("found multiple definitions for ClusterIssuer %s", ident)
 		}
+
+		identifiers.Insert(ident)
 	}
 
-	// sort all lists to ensure a stable output
+	// sort all lists to ensure a stable output; sorting must be stable, so
+	// objects with the same generateName keep their order
 
-	sort.Slice(result.Secrets, func(i, j int) bool {
+	sort.SliceStable(result.Secrets, func(i, j int) bool {
 		return resourceIsLess(&result.Secrets[i], &result.Secrets[j])
 	})
 
-	sort.Slice(result.Certificates, func(i, j int) bool {
+	sort.SliceStable(result.Certificates, func(i, j int) bool {
 		return resourceIsLess(&result.Certificates[i], &result.Certificates[j])
 	})
 
-	sort.Slice(result.Issuers, func(i, j int) bool {
+	sort.SliceStable(result.Issuers, func(i, j int) bool {
 		return resourceIsLess(&result.Issuers[i], &result.Issuers[j])
 	})
 
-	sort.Slice(result.ClusterIssuers, func(i, j int) bool {
+	sort.SliceStable(result.ClusterIssuers, func(i, j int) bool {
 		return resourceIsLess(&result.ClusterIssuers[i], &result.ClusterIssuers[j])
 	})
 
-	return result, nil
+	return result, loaded.warnings, nil
 }
 
+// getResourceIdentifier returns the namespace/name of an object, or an empty
+// string if the object only has a generateName.
 func getResourceIdentifier(res metav1.Object) (string, error) {
 	base, err := getResourceName(res)
 	if err != nil {
 		return "", err
 	}
 
+	if res.GetName() == "" {
+		return "", nil
+	}
+
 	if ns := res.GetNamespace(); ns != "" {
 		return ns + "/" + base, nil
 	}
@@ -181,7 +325,7 @@ func resourceIsLess(a, b metav1.Object) bool {
 	return nameA < nameB
 }
 
-func loadManifestsSource(result *types.PKI, opt *Options, source string) error {
+func loadManifestsSource(ctx context.Context, result *loadResult, opt *Options, source string) error {
 	if source == "-" {
 		// thank you https://stackoverflow.com/a/26567513
 		stat, _ := os.Stdin.Stat()
@@ -189,7 +333,7 @@ func loadManifestsSource(result *types.PKI, opt *Options, source string) error {
 			return errors.New("no data provided on stdin")
 		}
 
-		return loadManifestsSourceReader(result, opt, os.Stdin)
+		return loadManifestsSourceReader(ctx, result, opt, newDocumentReader(opt.MaxDocumentSize), "stdin", os.Stdin)
 	}
 
 	stat, err := os.Stat(source)
@@ -203,80 +347,137 @@ func loadManifestsSource(result *types.PKI, opt *Options, source string) error {
 			return fmt.Errorf("failed to determine absolute path: %w", err)
 		}
 
-		return loadManifestsSourceDirectory(result, opt, absSource)
+		return loadManifestsSourceDirectory(ctx, result, opt, absSource)
 	}
 
-	return loadManifestsSourceFile(result, opt, source)
+	return loadManifestsSourceFile(ctx, result, opt, newDocumentReader(opt.MaxDocumentSize), source)
 }
 
-const (
-	bufSize = 5 * 1024 * 1024
-)
-
-func loadManifestsSourceFile(result *types.PKI, opt *Options, source string) error {
+func loadManifestsSourceFile(ctx context.Context, result *loadResult, opt *Options, docs *documentReader, source string) error {
 	f, err := os.Open(source)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
 	defer f.Close()
 
-	return loadManifestsSourceReader(result, opt, f)
+	return loadManifestsSourceReader(ctx, result, opt, docs, source, f)
 }
 
-func loadManifestsSourceReader(result *types.PKI, opt *Options, source io.ReadCloser) error {
-	docSplitter := yamlutil.NewDocumentDecoder(source)
-	defer docSplitter.Close()
+func loadManifestsSourceReader(ctx context.Context, result *loadResult, opt *Options, docs *documentReader, name string, source io.Reader) error {
+	docs.Reset(source)
 
 	for i := 1; true; i++ {
-		buf := make([]byte, bufSize) // 5 MB, same as chunk size in decoder
-		read, err := docSplitter.Read(buf)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		doc, err := docs.Next()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				break
 			}
 
-			return fmt.Errorf("document %d is larger than the internal buffer", i)
-		}
-
-		fileContents, err := parseFileContents(opt, buf[:read])
-		if err != nil {
-			if errors.Is(err, io.EOF) {
+			// the reader has skipped the document and can continue
+			if errors.Is(err, ErrDocumentTooLarge) && opt.Lenient {
+				result.warn(name, i, err)
 				continue
 			}
-			return fmt.Errorf("document %d is invalid: %w", i, err)
+
+			return fmt.Errorf("failed to read document %d: %w", i, err)
 		}
 
-		if fileContents == nil {
-			continue
+		warn := func(err error) {
+			result.warn(name, i, err)
+		}
+
+		fileContents, errs := parseFileContents(opt, doc, warn)
+		for _, err := range errs {
+			if !opt.Lenient {
+				return fmt.Errorf("document %d is invalid: %w", i, err)
+			}
+
+			result.warn(name, i, err)
 		}
 
-		result.Secrets = append(result.Secrets, fileContents.Secrets...)
-		result.Certificates = append(result.Certificates, fileContents.Certificates...)
-		result.Issuers = append(result.Issuers, fileContents.Issuers...)
-		result.ClusterIssuers = append(result.ClusterIssuers, fileContents.ClusterIssuers...)
+		if fileContents != nil {
+			result.add(fileContents)
+		}
 	}
 
 	return nil
 }
 
-func loadManifestsSourceDirectory(result *types.PKI, opt *Options, rootDir string) error {
-	contents, err := os.ReadDir(rootDir)
+func loadManifestsSourceDirectory(ctx context.Context, result *loadResult, opt *Options, rootDir string) error {
+	var files []string
+
+	// WalkDir visits entries in lexical order, which together with merging the
+	// results in this order keeps the output (incl. warnings) deterministic.
+	err := filepath.WalkDir(rootDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to read directory %s: %w", path, err)
+		}
+
+		if !entry.IsDir() && hasExtension(entry.Name(), opt.FileExtensions) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read directory: %w", err)
+		return err
 	}
 
-	for _, entry := range contents {
-		fullPath := filepath.Join(rootDir, entry.Name())
+	workers := opt.Workers
+	if workers < 1 {
+		workers = 1
+	}
 
-		if entry.IsDir() {
-			if err := loadManifestsSourceDirectory(result, opt, fullPath); err != nil {
-				return fmt.Errorf("failed to read directory %s: %w", fullPath, err)
-			}
-		} else if hasExtension(entry.Name(), opt.FileExtensions) {
-			if err := loadManifestsSourceFile(result, opt, fullPath); err != nil {
-				return fmt.Errorf("failed to read file %s: %w", fullPath, err)
+	results := make([]*loadResult, len(files))
+	errs := make([]error, len(files))
+	indexes := make(chan int)
+
+	var (
+		wg     sync.WaitGroup
+		failed atomic.Bool
+	)
+
+	for range min(workers, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			docs := newDocumentReader(opt.MaxDocumentSize)
+			for idx := range indexes {
+				// no need to do more work if the result will be discarded anyway
+				if failed.Load() || ctx.Err() != nil {
+					continue
+				}
+
+				results[idx] = newLoadResult()
+				if err := loadManifestsSourceFile(ctx, results[idx], opt, docs, files[idx]); err != nil {
+					errs[idx] = fmt.Errorf("failed to read file %s: %w", files[idx], err)
+					failed.Store(true)
+				}
 			}
-		}
+		}()
+	}
+
+	for idx := range files {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for _, fileResult := range results {
+		result.merge(fileResult)
 	}
 
 	return nil
@@ -295,12 +496,18 @@ func hasExtension(filename string, extensions []string) bool {
 	return false
 }
 
-func parseFileContents(opt *Options, data []byte) (*types.PKI, error) {
+// parseFileContents returns all valid objects from the given document, plus an
+// error for each object that could not be parsed. Empty documents yield neither.
+func parseFileContents(opt *Options, data []byte, warn func(error)) (*types.PKI, []error) {
 	candidate := unstructured.Unstructured{}
 
 	err := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(data), 1024).Decode(&candidate)
 	if err != nil {
-		return nil, fmt.Errorf("document is not valid Kubernetes YAML: %w", err)
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		return nil, []error{fmt.Errorf("%w: %w", ErrInvalidDocument, err)}
 	}
 
 	result := &types.PKI{
@@ -310,39 +517,78 @@ func parseFileContents(opt *Options, data []byte) (*types.PKI, error) {
 		ClusterIssuers: []certmanagerv1.ClusterIssuer{},
 	}
 
-	if err := parseUnstructured(opt, candidate, result); err != nil {
-		return nil, err
-	}
-
-	return result, nil
+	return result, parseUnstructured(opt, candidate, result, warn)
 }
 
-func parseUnstructured(opt *Options, candidate unstructured.Unstructured, result *types.PKI) error {
+// parseUnstructured adds the candidate to the result and returns one error
+// for every object (the candidate itself or any of its list items) that was
+// invalid and therefore skipped. Non-fatal problems are reported via warn.
+func parseUnstructured(opt *Options, candidate unstructured.Unstructured, result *types.PKI, warn func(error)) []error {
 	// recurse into lists
 	if candidate.IsList() {
 		list, err := candidate.ToList()
 		if err != nil {
-			return fmt.Errorf("object looks like List, but: %w", err)
+			return []error{fmt.Errorf("object looks like List, but: %w: %w", ErrInvalidObject, err)}
 		}
 
+		var errs []error
 		for _, obj := range list.Items {
-			if err := parseUnstructured(opt, obj, result); err != nil {
-				return err
-			}
+			errs = append(errs, parseUnstructured(opt, obj, result, warn)...)
 		}
 
-		return nil
+		return errs
+	}
+
+	if err := parseObject(opt, candidate, result, warn); err != nil {
+		return []error{err}
 	}
 
+	return nil
+}
+
+var (
+	certManagerGroups = sets.New("cert-manager.io", "certmanager.k8s.io")
+	certManagerKinds  = sets.New("Certificate", "Issuer", "ClusterIssuer")
+)
+
+func parseObject(opt *Options, candidate unstructured.Unstructured, result *types.PKI, warn func(error)) error {
 	makeError := func(kind string, err error) error {
 		return fmt.Errorf("document is not valid %s: %w", kind, err)
 	}
 
-	switch candidate.GroupVersionKind().GroupKind().String() {
+	gvk := candidate.GroupVersionKind()
+
+	switch {
+	case gvk.Kind == "Secret" && gvk.Group == "" && gvk.Version != "v1":
+		return fmt.Errorf("Secret %q uses %w %s", candidate.GetName(), ErrUnknownAPIVersion, gvk.GroupVersion())
+
+	// other projects have their own Certificate CRDs, so these are not invalid,
+	// but should not be dropped silently either
+	case (gvk.Kind == "Secret" && gvk.Group != "") || (certManagerKinds.Has(gvk.Kind) && !certManagerGroups.Has(gvk.Group)):
+		warn(fmt.Errorf("%s %q uses %w %s and is ignored", gvk.Kind, candidate.GetName(), ErrUnknownAPIVersion, gvk.GroupVersion()))
+		return nil
+	}
+
+	if certManagerGroups.Has(gvk.Group) && certManagerKinds.Has(gvk.Kind) && gvk.GroupVersion() != certmanagerv1.SchemeGroupVersion {
+		if !legacyVersions.Has(gvk.GroupVersion()) {
+			return fmt.Errorf("%s %q uses %w %s", gvk.Kind, candidate.GetName(), ErrUnknownAPIVersion, gvk.GroupVersion())
+		}
+
+		lost := convertLegacyObject(&candidate)
+		if len(lost) > 0 {
+			warn(fmt.Errorf("%s %q was converted from %s, dropping %s", gvk.Kind, candidate.GetName(), gvk.GroupVersion(), strings.Join(lost, ", ")))
+		} else {
+			warn(fmt.Errorf("%s %q was converted from %s", gvk.Kind, candidate.GetName(), gvk.GroupVersion()))
+		}
+
+		gvk = candidate.GroupVersionKind()
+	}
+
+	switch gvk.GroupKind().String() {
 	case "Secret":
 		secret := corev1.Secret{}
 		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(candidate.Object, &secret); err != nil {
-			return makeError("Secret", err)
+			return makeError("Secret", fmt.Errorf("%w: %w", ErrInvalidObject, err))
 		}
 
 		// ignore non-TLS secrets as they should not influence the PKI structure (there might be a secret
@@ -361,7 +607,7 @@ func parseUnstructured(opt *Options, candidate unstructured.Unstructured, result
 	case "Certificate.cert-manager.io":
 		cert := certmanagerv1.Certificate{}
 		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(candidate.Object, &cert); err != nil {
-			return makeError("Certificate", err)
+			return makeError("Certificate", fmt.Errorf("%w: %w", ErrInvalidObject, err))
 		}
 		if err := injectNamespace(&cert, opt); err != nil {
 			return makeError("Certificate", err)
@@ -373,7 +619,7 @@ func parseUnstructured(opt *Options, candidate unstructured.Unstructured, result
 	case "Issuer.cert-manager.io":
 		issuer := certmanagerv1.Issuer{}
 		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(candidate.Object, &issuer); err != nil {
-			return makeError("Issuer", err)
+			return makeError("Issuer", fmt.Errorf("%w: %w", ErrInvalidObject, err))
 		}
 		if err := injectNamespace(&issuer, opt); err != nil {
 			return makeError("Issuer", err)
@@ -385,12 +631,14 @@ func parseUnstructured(opt *Options, candidate unstructured.Unstructured, result
 	case "ClusterIssuer.cert-manager.io":
 		clusterIssuer := certmanagerv1.ClusterIssuer{}
 		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(candidate.Object, &clusterIssuer); err != nil {
-			return makeError("ClusterIssuer", err)
+			return makeError("ClusterIssuer", fmt.Errorf("%w: %w", ErrInvalidObject, err))
 		}
 		// strip out misleading metadata
 		clusterIssuer.Namespace = ""
 
-		result.ClusterIssuers = append(result.ClusterIssuers, clusterIssuer)
+		if labelsMatchOpt(&clusterIssuer, opt) {
+			result.ClusterIssuers = append(result.ClusterIssuers, clusterIssuer)
+		}
 	}
 
 	return nil
@@ -398,16 +646,51 @@ func parseUnstructured(opt *Options, candidate unstructured.Unstructured, result
 
 func injectNamespace(res metav1.Object, opt *Options) error {
 	if res.GetNamespace() == "" {
-		if opt.Namespace == "" {
-			return errors.New("no metadata.namespace set and no --namespace provided")
+		if opt.DefaultNamespace == "" {
+			return fmt.Errorf("%w and no --namespace provided", ErrMissingNamespace)
 		}
 
-		res.SetNamespace(opt.Namespace)
+		res.SetNamespace(opt.DefaultNamespace)
 	}
 
 	return nil
 }
 
 func resourceMatchesOpt(res metav1.Object, opt *Options) bool {
-	return opt.Namespace == "" || res.GetNamespace() == opt.Namespace
+	ns := res.GetNamespace()
+
+	if len(opt.IncludeNamespaces) > 0 && !matchesAnyGlob(ns, opt.IncludeNamespaces) {
+		return false
+	}
+
+	if matchesAnyGlob(ns, opt.ExcludeNamespaces) {
+		return false
+	}
+
+	return labelsMatchOpt(res, opt)
+}
+
+func labelsMatchOpt(res metav1.Object, opt *Options) bool {
+	return opt.LabelSelector == nil || opt.LabelSelector.Matches(labels.Set(res.GetLabels()))
+}
+
+func matchesAnyGlob(s string, patterns []string) bool {
+	for _, pattern := range patterns {
+		// patterns have been validated in LoadPKI already
+		if matched, _ := path.Match(pattern, s); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	benchmarkObjects = 50_000
+	benchmarkFiles   = 100
+)
+
+// writeDump generates a dump with the given number of objects, spread across
+// files in dir, and returns the filenames. Every group of four objects forms
+// a small PKI (Issuer, CA Certificate, leaf Certificate and Secret).
+func writeDump(b *testing.B, dir string, objects int, files int) []string {
+	b.Helper()
+
+	filenames := make([]string, 0, files)
+	perFile := objects / files
+
+	for f := range files {
+		filename := filepath.Join(dir, fmt.Sprintf("dump-%03d.yaml", f))
+		filenames = append(filenames, filename)
+
+		out, err := os.Create(filename)
+		if err != nil {
+			b.Fatalf("Failed to create dump: %v", err)
+		}
+
+		w := bufio.NewWriter(out)
+		for i := range perFile {
+			id := f*perFile + i
+			ns := fmt.Sprintf("ns-%d", id/400)
+
+			switch id % 4 {
+			case 0:
+				fmt.Fprintf(w, "---\napiVersion: cert-manager.io/v1\nkind: Issuer\nmetadata:\n  name: issuer-%d\n  namespace: %s\nspec:\n  ca:\n    secretName: ca-%d\n", id, ns, id+1)
+			case 1:
+				fmt.Fprintf(w, "---\napiVersion: cert-manager.io/v1\nkind: Certificate\nmetadata:\n  name: ca-%d\n  namespace: %s\nspec:\n  isCA: true\n  commonName: ca-%d\n  secretName: ca-%d\n  issuerRef:\n    name: issuer-%d\n", id, ns, id, id, id-1)
+			case 2:
+				fmt.Fprintf(w, "---\napiVersion: cert-manager.io/v1\nkind: Certificate\nmetadata:\n  name: leaf-%d\n  namespace: %s\nspec:\n  dnsNames:\n  - leaf-%d.example.com\n  secretName: leaf-%d\n  issuerRef:\n    name: issuer-%d\n", id, ns, id, id, id-2)
+			case 3:
+				fmt.Fprintf(w, "---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: leaf-%d\n  namespace: %s\ntype: kubernetes.io/tls\ndata:\n  tls.crt: \"\"\n  tls.key: \"\"\n", id-1, ns)
+			}
+		}
+
+		if err := w.Flush(); err != nil {
+			b.Fatalf("Failed to write dump: %v", err)
+		}
+
+		if err := out.Close(); err != nil {
+			b.Fatalf("Failed to write dump: %v", err)
+		}
+	}
+
+	return filenames
+}
+
+func TestLoadSourcesDuplicates(t *testing.T) {
+	certificate := func(meta string) string {
+		return "---\napiVersion: cert-manager.io/v1\nkind: Certificate\nmetadata:\n  namespace: default\n  " + meta + "\nspec:\n  secretName: tls\n  issuerRef:\n    name: ca\n"
+	}
+
+	testcases := []struct {
+		name     string
+		input    string
+		expected int
+		invalid  bool
+	}{
+		{
+			name:     "distinct names",
+			input:    certificate("name: a") + certificate("name: b"),
+			expected: 2,
+		},
+		{
+			name:    "same name",
+			input:   certificate("name: a") + certificate("name: a"),
+			invalid: true,
+		},
+		{
+			name:     "same generateName",
+			input:    certificate("generateName: a-") + certificate("generateName: a-"),
+			expected: 2,
+		},
+		{
+			name:     "generateName equal to a name",
+			input:    certificate("name: a") + certificate("generateName: a"),
+			expected: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pki, _, err := LoadSources(context.Background(), []Source{FromReader("test", strings.NewReader(tc.input))}, nil)
+			if tc.invalid {
+				if err == nil {
+					t.Fatal("Expected an error, but got none.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Failed to load PKI: %v", err)
+			}
+
+			if len(pki.Certificates) != tc.expected {
+				t.Fatalf("Expected %d Certificates, got %d", tc.expected, len(pki.Certificates))
+			}
+		})
+	}
+}
+
+func BenchmarkLoadPKI(b *testing.B) {
+	dir := b.TempDir()
+	writeDump(b, dir, benchmarkObjects, benchmarkFiles)
+
+	// the same objects again, but in one large file
+	single := writeDump(b, b.TempDir(), benchmarkObjects, 1)[0]
+
+	benchmarks := []struct {
+		name    string
+		source  string
+		workers int
+	}{
+		{name: "file", source: single, workers: 1},
+		{name: "directory/1-worker", source: dir, workers: 1},
+		{name: "directory/default-workers", source: dir},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			opt := NewDefaultOptions()
+			if bm.workers > 0 {
+				opt.Workers = bm.workers
+			}
+
+			b.ReportAllocs()
+
+			for range b.N {
+				pki, _, err := LoadPKI(context.Background(), []string{bm.source}, opt)
+				if err != nil {
+					b.Fatalf("Failed to load PKI: %v", err)
+				}
+
+				if loaded := len(pki.Issuers) + len(pki.Certificates) + len(pki.Secrets); loaded != benchmarkObjects {
+					b.Fatalf("Expected %d objects, got %d", benchmarkObjects, loaded)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadSourcesWarnings(t *testing.T) {
+	certificate := func(name string) string {
+		return "---\napiVersion: cert-manager.io/v1\nkind: Certificate\nmetadata:\n  name: " + name + "\n  namespace: default\nspec:\n  secretName: tls\n  issuerRef:\n    name: ca\n"
+	}
+
+	testcases := []struct {
+		name     string
+		input    string
+		expected error
+		// strict is true if the document also fails loading without lenient mode
+		strict bool
+	}{
+		{
+			name:     "invalid YAML",
+			input:    "---\nkind: [\n",
+			expected: ErrInvalidDocument,
+			strict:   true,
+		},
+		{
+			name:     "oversize document",
+			input:    "---\napiVersion: v1\nkind: ConfigMap\ndata:\n  x: " + strings.Repeat("x", 1024) + "\n",
+			expected: ErrDocumentTooLarge,
+			strict:   true,
+		},
+		{
+			name:     "invalid object",
+			input:    "---\napiVersion: cert-manager.io/v1\nkind: Certificate\nmetadata:\n  name: a\n  namespace: default\nspec: invalid\n",
+			expected: ErrInvalidObject,
+			strict:   true,
+		},
+		{
+			name:     "missing namespace",
+			input:    "---\napiVersion: cert-manager.io/v1\nkind: Issuer\nmetadata:\n  name: a\nspec:\n  selfSigned: {}\n",
+			expected: ErrMissingNamespace,
+			strict:   true,
+		},
+		{
+			name:     "unknown cert-manager apiVersion",
+			input:    "---\napiVersion: cert-manager.io/v2\nkind: Certificate\nmetadata:\n  name: a\n  namespace: default\n",
+			expected: ErrUnknownAPIVersion,
+			strict:   true,
+		},
+		{
+			name:     "unknown Secret apiVersion",
+			input:    "---\napiVersion: v2\nkind: Secret\nmetadata:\n  name: a\n  namespace: default\n",
+			expected: ErrUnknownAPIVersion,
+			strict:   true,
+		},
+		{
+			name:     "Certificate from another API group",
+			input:    "---\napiVersion: acm.services.k8s.aws/v1alpha1\nkind: Certificate\nmetadata:\n  name: a\n  namespace: default\n",
+			expected: ErrUnknownAPIVersion,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			load := func(lenient bool) (int, []Warning, error) {
+				opt := NewDefaultOptions()
+				opt.Lenient = lenient
+				opt.MaxDocumentSize = 1024
+
+				pki, warnings, err := LoadSources(context.Background(), []Source{FromReader("test", strings.NewReader(certificate("before")+tc.input+certificate("after")))}, opt)
+				if err != nil {
+					return 0, nil, err
+				}
+
+				return len(pki.Certificates), warnings, nil
+			}
+
+			certificates, warnings, err := load(true)
+			if err != nil {
+				t.Fatalf("Failed to load PKI: %v", err)
+			}
+
+			// the Certificates in front of and behind the skipped document
+			// must both be loaded
+			if certificates != 2 {
+				t.Errorf("Expected 2 Certificates, got %d", certificates)
+			}
+
+			if len(warnings) != 1 {
+				t.Fatalf("Expected 1 warning, got %d: %v", len(warnings), warnings)
+			}
+
+			if !errors.Is(warnings[0].Err, tc.expected) {
+				t.Errorf("Expected warning to wrap %q, got %v", tc.expected, warnings[0].Err)
+			}
+
+			if warnings[0].Document != 2 {
+				t.Errorf("Expected warning for document 2, got %d", warnings[0].Document)
+			}
+
+			_, _, err = load(false)
+			if tc.strict && !errors.Is(err, tc.expected) {
+				t.Errorf("Expected strict loading to fail with %q, got %v", tc.expected, err)
+			}
+			if !tc.strict && err != nil {
+				t.Errorf("Expected strict loading to succeed, got %v", err)
+			}
+		})
+	}
+}
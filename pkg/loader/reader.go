@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	readBufferSize = 64 * 1024
+)
+
+// ErrDocumentTooLarge is returned for documents larger than the configured
+// limit. The reader skips the rest of such a document, so reading can continue
+// with the next one.
+var ErrDocumentTooLarge = errors.New("document is too large")
+
+// documentReader splits a YAML stream into its individual documents. Unlike
+// the decoders in k8s.io/apimachinery, it has no upper limit on the document
+// size unless one is given, and reuses its internal buffers, so the slices
+// returned by Next are only valid until the next call to Next or Reset.
+type documentReader struct {
+	r     *bufio.Reader
+	buf   bytes.Buffer
+	size  int
+	limit int
+	eof   bool
+}
+
+// newDocumentReader returns a reader for documents up to limit bytes; 0 means
+// no limit.
+func newDocumentReader(limit int) *documentReader {
+	return &documentReader{
+		r:     bufio.NewReaderSize(nil, readBufferSize),
+		limit: limit,
+	}
+}
+
+// Reset makes the reader read from r, keeping all previously allocated memory.
+func (d *documentReader) Reset(r io.Reader) {
+	d.r.Reset(r)
+	d.buf.Reset()
+	d.eof = false
+}
+
+// Next returns the next document in the stream or io.EOF if there are no
+// more documents. Documents can be empty or only contain comments.
+func (d *documentReader) Next() ([]byte, error) {
+	if d.eof {
+		return nil, io.EOF
+	}
+
+	d.buf.Reset()
+	d.size = 0
+
+	// A line can be longer than the bufio buffer, in which case it is returned
+	// in chunks and only the first one can possibly be a document separator.
+	lineStart := true
+
+	for {
+		chunk, err := d.r.ReadSlice('\n')
+		if err != nil && !errors.Is(err, bufio.ErrBufferFull) {
+			if !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+
+			d.eof = true
+			d.write(chunk)
+
+			if d.size == 0 {
+				return nil, io.EOF
+			}
+
+			return d.document()
+		}
+
+		if lineStart && isDocumentSeparator(chunk) {
+			// a leading separator does not end an (empty) document
+			if d.size > 0 {
+				return d.document()
+			}
+
+			continue
+		}
+
+		d.write(chunk)
+		lineStart = !errors.Is(err, bufio.ErrBufferFull)
+	}
+}
+
+// write adds the chunk to the current document, but stops buffering once the
+// document exceeds the limit.
+func (d *documentReader) write(chunk []byte) {
+	d.size += len(chunk)
+
+	if d.limit == 0 || d.size <= d.limit {
+		d.buf.Write(chunk)
+	}
+}
+
+func (d *documentReader) document() ([]byte, error) {
+	if d.limit > 0 && d.size > d.limit {
+		return nil, fmt.Errorf("%w (%d bytes, limit is %d)", ErrDocumentTooLarge, d.size, d.limit)
+	}
+
+	return d.buf.Bytes(), nil
+}
+
+var separator = []byte("---")
+
+// isDocumentSeparator returns true for lines that consist of "---", optionally
+// followed by whitespace and/or a comment.
+func isDocumentSeparator(line []byte) bool {
+	rest, found := bytes.CutPrefix(line, separator)
+	if !found {
+		return false
+	}
+
+	rest = bytes.TrimSpace(rest)
+
+	return len(rest) == 0 || rest[0] == '#'
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func readAllDocuments(t *testing.T, docs *documentReader, input string) []string {
+	t.Helper()
+
+	docs.Reset(strings.NewReader(input))
+
+	var result []string
+	for {
+		doc, err := docs.Next()
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		if err != nil {
+			t.Fatalf("Failed to read document: %v", err)
+		}
+
+		// the returned slice is only valid until the next call to Next
+		result = append(result, string(doc))
+	}
+}
+
+func TestDocumentReader(t *testing.T) {
+	huge := "data: " + strings.Repeat("x", 3*readBufferSize) + "\n"
+
+	testcases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "empty stream",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name:     "single document",
+			input:    "a: 1\nb: 2\n",
+			expected: []string{"a: 1\nb: 2\n"},
+		},
+		{
+			name:     "missing trailing newline",
+			input:    "a: 1\n---\nb: 2",
+			expected: []string{"a: 1\n", "b: 2"},
+		},
+		{
+			name:     "leading separator",
+			input:    "---\na: 1\n",
+			expected: []string{"a: 1\n"},
+		},
+		{
+			name:     "trailing separator",
+			input:    "a: 1\n---\n",
+			expected: []string{"a: 1\n"},
+		},
+		{
+			name:     "separator with comment and whitespace",
+			input:    "a: 1\n---  # next\nb: 2\n--- \nc: 3\n",
+			expected: []string{"a: 1\n", "b: 2\n", "c: 3\n"},
+		},
+		{
+			name:     "separator-like lines are content",
+			input:    "a: ---\n----\n--- b\n",
+			expected: []string{"a: ---\n----\n--- b\n"},
+		},
+		{
+			name:     "consecutive separators",
+			input:    "a: 1\n---\n---\nb: 2\n",
+			expected: []string{"a: 1\n", "b: 2\n"},
+		},
+		{
+			name:     "document larger than the read buffer",
+			input:    "a: 1\n---\n" + huge + "---\nb: 2\n",
+			expected: []string{"a: 1\n", huge, "b: 2\n"},
+		},
+		{
+			name:     "huge document without trailing newline",
+			input:    strings.TrimSuffix(huge, "\n"),
+			expected: []string{strings.TrimSuffix(huge, "\n")},
+		},
+		{
+			// only the start of a line can be a separator, not the start of
+			// a chunk in the middle of a long line
+			name:     "separator at chunk boundary",
+			input:    strings.Repeat("x", readBufferSize) + "---\nb: 2\n",
+			expected: []string{strings.Repeat("x", readBufferSize) + "---\nb: 2\n"},
+		},
+	}
+
+	// reuse one reader for all testcases to ensure Reset works
+	docs := newDocumentReader(0)
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := readAllDocuments(t, docs, tc.input)
+
+			if len(result) != len(tc.expected) {
+				t.Fatalf("Expected %d documents, got %d: %q", len(tc.expected), len(result), result)
+			}
+
+			for i := range result {
+				if result[i] != tc.expected[i] {
+					t.Errorf("Document %d: expected %q, got %q", i, tc.expected[i], result[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDocumentReaderLimit(t *testing.T) {
+	input := "a: 1\n---\n" + strings.Repeat("b: 2\n", 10) + "---\nc: 3\n"
+
+	docs := newDocumentReader(20)
+	docs.Reset(strings.NewReader(input))
+
+	doc, err := docs.Next()
+	if err != nil {
+		t.Fatalf("Failed to read first document: %v", err)
+	}
+
+	if string(doc) != "a: 1\n" {
+		t.Fatalf("Expected first document %q, got %q", "a: 1\n", doc)
+	}
+
+	if _, err := docs.Next(); !errors.Is(err, ErrDocumentTooLarge) {
+		t.Fatalf("Expected ErrDocumentTooLarge, got %v", err)
+	}
+
+	// the oversize document must be skipped entirely
+	doc, err = docs.Next()
+	if err != nil {
+		t.Fatalf("Failed to read document after oversize document: %v", err)
+	}
+
+	if string(doc) != "c: 3\n" {
+		t.Fatalf("Expected last document %q, got %q", "c: 3\n", doc)
+	}
+
+	if _, err := docs.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+}
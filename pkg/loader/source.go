@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+)
+
+// Source is a single input for LoadSources. Exactly one of Path, Reader or
+// Objects must be set.
+type Source struct {
+	// Path is a file or directory; "-" means stdin.
+	Path string
+	// Reader provides a stream of YAML or JSON documents.
+	Reader io.Reader
+	// Objects are already decoded Kubernetes objects, either typed or
+	// *unstructured.Unstructured.
+	Objects []runtime.Object
+	// Name identifies Reader and Objects sources in warnings and errors.
+	Name string
+}
+
+func FromPath(path string) Source {
+	return Source{Path: path}
+}
+
+func FromReader(name string, r io.Reader) Source {
+	return Source{Name: name, Reader: r}
+}
+
+func FromObjects(name string, objects ...runtime.Object) Source {
+	return Source{Name: name, Objects: objects}
+}
+
+func (s Source) String() string {
+	if s.Path != "" {
+		return s.Path
+	}
+
+	return s.Name
+}
+
+func loadSource(ctx context.Context, result *loadResult, opt *Options, source Source) error {
+	switch {
+	case source.Path != "":
+		return loadManifestsSource(ctx, result, opt, source.Path)
+	case source.Reader != nil:
+		return loadManifestsSourceReader(ctx, result, opt, newDocumentReader(opt.MaxDocumentSize), source.Name, source.Reader)
+	case source.Objects != nil:
+		return loadObjects(ctx, result, opt, source.Name, source.Objects)
+	default:
+		return errors.New("source has neither path, reader nor objects")
+	}
+}
+
+func loadObjects(ctx context.Context, result *loadResult, opt *Options, name string, objects []runtime.Object) error {
+	for i, obj := range objects {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		warn := func(err error) {
+			result.warn(name, i+1, err)
+		}
+
+		var errs []error
+
+		candidate, err := toUnstructured(obj)
+		if err != nil {
+			errs = []error{fmt.Errorf("%w: %w", ErrInvalidObject, err)}
+		} else {
+			errs = parseUnstructured(opt, *candidate, result.PKI, warn)
+		}
+
+		for _, err := range errs {
+			if !opt.Lenient {
+				return fmt.Errorf("object %d is invalid: %w", i+1, err)
+			}
+
+			warn(err)
+		}
+	}
+
+	return nil
+}
+
+// scheme is used to determine the kind of typed objects without TypeMeta.
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(certmanagerv1.AddToScheme(scheme))
+}
+
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.DeepCopy(), nil
+	}
+
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: data}
+
+	if u.GetKind() == "" {
+		gvks, _, err := scheme.ObjectKinds(obj)
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine kind: %w", err)
+		}
+
+		u.SetGroupVersionKind(gvks[0])
+	}
+
+	return u, nil
+}
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package pkigraph
+
+// EdgeType describes how two nodes are related, phrased in the direction
+// diagrams are usually drawn (from issuer to issued).
+type EdgeType string
+
+const (
+	// EdgeIssues connects an Issuer or ClusterIssuer to a Certificate.
+	EdgeIssues EdgeType = "issues"
+	// EdgeCreates connects a Certificate to the Secret it produces.
+	EdgeCreates EdgeType = "creates"
+	// EdgeCAFor connects a CA Secret (or the Certificate producing it, if
+	// Secrets are hidden) to the Issuer or ClusterIssuer using it.
+	EdgeCAFor EdgeType = "CA for"
+)
+
+// EdgeTypeOf returns the type of the edge between a node and a node it
+// depends on, i.e. the source and target of an edge in the raw graph.
+func EdgeTypeOf(dependent, dependency Node) EdgeType {
+	switch {
+	case dependent.Certificate != nil:
+		return EdgeIssues
+	case dependent.Secret != nil:
+		return EdgeCreates
+	default:
+		return EdgeCAFor
+	}
+}
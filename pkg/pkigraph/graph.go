@@ -4,6 +4,8 @@
 package pkigraph
 
 import (
+	"fmt"
+
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/dominikbraun/graph"
 
@@ -15,6 +17,9 @@ import (
 
 type Graph struct {
 	g graph.Graph[string, Node]
+
+	// labels are custom node labels, see SetLabels.
+	labels map[string][]string
 }
 
 func New() Graph {
@@ -32,24 +37,44 @@ type Options struct {
 func NewFromPKI(pki *types.PKI, opt Options) Graph {
 	pg := New()
 
+	// build nodes for all PKI elements; objects with the same generateName
+	// are numbered in the order they were loaded
+	numbered := ordinals{}
+
+	certs := make([]Node, 0, len(pki.Certificates))
+	for _, cert := range pki.Certificates {
+		certs = append(certs, numbered.number(certificateNode(cert)))
+	}
+
+	issuers := make([]Node, 0, len(pki.Issuers))
+	for _, issuer := range pki.Issuers {
+		issuers = append(issuers, numbered.number(issuerNode(issuer)))
+	}
+
+	clusterIssuers := make([]Node, 0, len(pki.ClusterIssuers))
+	for _, clusterIssuer := range pki.ClusterIssuers {
+		clusterIssuers = append(clusterIssuers, numbered.number(clusterIssuerNode(clusterIssuer)))
+	}
+
 	// add vertices for all PKI elements
 	if opt.ShowSecrets {
 		for _, secret := range pki.Secrets {
-			pg.g.AddVertex(secretNode(secret))
+			pg.g.AddVertex(numbered.number(secretNode(secret)))
 		}
 	}
-	for _, cert := range pki.Certificates {
-		pg.g.AddVertex(certificateNode(cert))
+	for _, node := range certs {
+		pg.g.AddVertex(node)
 	}
-	for _, issuer := range pki.Issuers {
-		pg.g.AddVertex(issuerNode(issuer))
+	for _, node := range issuers {
+		pg.g.AddVertex(node)
 	}
-	for _, clusterIssuer := range pki.ClusterIssuers {
-		pg.g.AddVertex(clusterIssuerNode(clusterIssuer))
+	for _, node := range clusterIssuers {
+		pg.g.AddVertex(node)
 	}
 
-	for _, cert := range pki.Certificates {
-		hash := certificateHash(cert)
+	for _, node := range certs {
+		cert := node.Certificate
+		hash := node.Hash()
 
 		if opt.ShowSecrets {
 			// create an edge between a cert and the secret it produces
@@ -75,43 +100,43 @@ func NewFromPKI(pki *types.PKI, opt Options) Graph {
 	}
 
 	if opt.ShowSecrets {
-		for _, issuer := range pki.Issuers {
-			hash := issuerHash(issuer)
+		for _, node := range issuers {
+			issuer := node.Issuer
 
 			// connect the secret that a CA issuer uses to sign new certs
 			if caConfig := issuer.Spec.CA; caConfig != nil && caConfig.SecretName != "" {
 				if secretNode, ok := pg.ensureSecret(opt, issuer.Namespace, caConfig.SecretName); ok {
-					pg.g.AddEdge(hash, secretNode.Hash())
+					pg.g.AddEdge(node.Hash(), secretNode.Hash())
 				}
 			}
 		}
 
-		for _, clusterIssuer := range pki.ClusterIssuers {
-			hash := clusterIssuerHash(clusterIssuer)
+		for _, node := range clusterIssuers {
+			clusterIssuer := node.ClusterIssuer
 
 			// connect the secret that a CA cluster issuer uses to sign new certs;
 			// note that since CI's are cluster-scoped, the special cert-manager resources namespace
 			// is used to find the secrets.
 			if caConfig := clusterIssuer.Spec.CA; caConfig != nil && caConfig.SecretName != "" {
 				if secretNode, ok := pg.ensureSecret(opt, opt.ClusterResourceNamespace, caConfig.SecretName); ok {
-					pg.g.AddEdge(hash, secretNode.Hash())
+					pg.g.AddEdge(node.Hash(), secretNode.Hash())
 				}
 			}
 		}
 	} else {
 		// If secrets are not included, we can still link (cluster)issuers (CAs only) to their
 		// secrets based on the secretName ref present in both the issuers and the certificates.
-		for _, issuer := range pki.Issuers {
-			caConfig := issuer.Spec.CA
+		for _, node := range issuers {
+			caConfig := node.Issuer.Spec.CA
 			if caConfig != nil && caConfig.SecretName != "" {
-				pg.spanSecretEdge(pki, issuerHash(issuer), caConfig.SecretName)
+				pg.spanSecretEdge(certs, node.Hash(), caConfig.SecretName)
 			}
 		}
 
-		for _, clusterIssuer := range pki.ClusterIssuers {
-			caConfig := clusterIssuer.Spec.CA
+		for _, node := range clusterIssuers {
+			caConfig := node.ClusterIssuer.Spec.CA
 			if caConfig != nil && caConfig.SecretName != "" {
-				pg.spanSecretEdge(pki, clusterIssuerHash(clusterIssuer), caConfig.SecretName)
+				pg.spanSecretEdge(certs, node.Hash(), caConfig.SecretName)
 			}
 		}
 	}
@@ -122,15 +147,14 @@ func NewFromPKI(pki *types.PKI, opt Options) Graph {
 // spanSecretEdge creates and edge between a node that references a secret, and
 // certificate(s) (usually one) that create that secret. This is used whenever
 // secrets are not included in the graph for brevity.
-func (g *Graph) spanSecretEdge(pki *types.PKI, sourceHash string, secretName string) {
-	for _, cert := range pki.Certificates {
-		if cert.Spec.SecretName != secretName {
+func (g *Graph) spanSecretEdge(certs []Node, sourceHash string, secretName string) {
+	for _, cert := range certs {
+		if cert.Certificate.Spec.SecretName != secretName {
 			continue
 		}
 
 		// create an edge between a cert and the issuer that will make use of it
-		cHash := certificateHash(cert)
-		g.g.AddEdge(sourceHash, cHash)
+		g.g.AddEdge(sourceHash, cert.Hash())
 	}
 }
 
@@ -180,3 +204,81 @@ func (g *Graph) ensureClusterIssuer(opt Options, name string) (Node, bool) {
 func (g *Graph) Raw() graph.Graph[string, Node] {
 	return g.g
 }
+
+// SetLabels computes a custom label for every node in the graph. Labels can
+// consist of multiple lines and renderers should use them instead of the
+// node's name.
+func (g *Graph) SetLabels(label func(Node) ([]string, error)) error {
+	amap, err := g.g.AdjacencyMap()
+	if err != nil {
+		return err
+	}
+
+	labels := map[string][]string{}
+
+	for hash := range amap {
+		node, err := g.g.Vertex(hash)
+		if err != nil {
+			return err
+		}
+
+		lines, err := label(node)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", node.Kind(), node.Name(), err)
+		}
+
+		if len(lines) > 0 {
+			labels[hash] = lines
+		}
+	}
+
+	g.labels = labels
+
+	return nil
+}
+
+// Label returns the lines of a node's label, which is its name unless
+// custom labels have been set.
+func (g *Graph) Label(n Node) []string {
+	if lines, ok := g.labels[n.Hash()]; ok {
+		return lines
+	}
+
+	return []string{n.Name()}
+}
+
+// Subgraph returns a new graph containing only the nodes accepted by keep
+// and the edges between them.
+func (g *Graph) Subgraph(keep func(Node) bool) (Graph, error) {
+	sub := New()
+	sub.labels = g.labels
+
+	amap, err := g.g.AdjacencyMap()
+	if err != nil {
+		return sub, err
+	}
+
+	kept := map[string]bool{}
+
+	for hash := range amap {
+		node, err := g.g.Vertex(hash)
+		if err != nil {
+			return sub, err
+		}
+
+		if keep(node) {
+			sub.g.AddVertex(node)
+			kept[hash] = true
+		}
+	}
+
+	for source, targets := range amap {
+		for target := range targets {
+			if kept[source] && kept[target] {
+				sub.g.AddEdge(source, target)
+			}
+		}
+	}
+
+	return sub, nil
+}
@@ -6,6 +6,7 @@ package pkigraph
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
@@ -24,6 +25,10 @@ type Node struct {
 	// YAML manifests or if it was created based on reference names (e.g. a
 	// Certificate creating a Secret, but that Secret was not loaded in).
 	Synthetic bool
+
+	// ordinal tells apart objects that only have the same generateName, see
+	// objectHash.
+	ordinal int
 }
 
 func (n Node) Object() metav1.Object {
@@ -45,12 +50,96 @@ func (n Node) ObjectKind() string {
 	return objectKind(n.Object())
 }
 
+// Kind returns the Kubernetes kind of the node's object.
+func (n Node) Kind() string {
+	switch {
+	case n.Secret != nil:
+		return "Secret"
+	case n.Certificate != nil:
+		return "Certificate"
+	case n.Issuer != nil:
+		return "Issuer"
+	case n.ClusterIssuer != nil:
+		return "ClusterIssuer"
+	default:
+		panic("Invalid node: None of the four possible fields are set.")
+	}
+}
+
+// Name returns the object's name, falling back to its generateName.
+func (n Node) Name() string {
+	obj := n.Object()
+	if name := obj.GetName(); name != "" {
+		return name
+	}
+
+	return obj.GetGenerateName()
+}
+
+// Classes are all possible node classes, see Node.Class.
+var Classes = []string{"clusterissuer", "issuer", "ca", "certificate", "secret"}
+
+// Class returns the node's class used for styling, which is its lowercase
+// kind, except for CA Certificates, which are "ca".
+func (n Node) Class() string {
+	if n.IsCA() {
+		return "ca"
+	}
+
+	return n.ObjectKind()
+}
+
+// TypeName returns a human readable type, e.g. "CA Certificate".
+func (n Node) TypeName() string {
+	if n.IsCA() {
+		return "CA Certificate"
+	}
+
+	return n.Kind()
+}
+
+// IsCA returns true for Certificates that are CAs.
+func (n Node) IsCA() bool {
+	return n.Certificate != nil && n.Certificate.Spec.IsCA
+}
+
+// IssuerType returns the configured issuer type (e.g. "ca" or "acme") for
+// Issuers and ClusterIssuers, and an empty string otherwise or if the type
+// is unknown (e.g. for synthetic nodes).
+func (n Node) IssuerType() string {
+	var spec *certmanagerv1.IssuerSpec
+
+	switch {
+	case n.Issuer != nil:
+		spec = &n.Issuer.Spec
+	case n.ClusterIssuer != nil:
+		spec = &n.ClusterIssuer.Spec
+	default:
+		return ""
+	}
+
+	switch {
+	case spec.CA != nil:
+		return "ca"
+	case spec.SelfSigned != nil:
+		return "selfSigned"
+	case spec.ACME != nil:
+		return "acme"
+	case spec.Vault != nil:
+		return "vault"
+	case spec.Venafi != nil:
+		return "venafi"
+	default:
+		return ""
+	}
+}
+
 func (n Node) Hash() string {
-	return objectHash(n.Object())
+	return objectHash(n.Object(), n.ordinal)
 }
 
 func nodeHash(n Node) string {
-	return objectHash(n.Object())
+	return n.Hash()
 }
 
 func objectKind(obj metav1.Object) string {
@@ -62,13 +151,26 @@ func objectKind(obj metav1.Object) string {
 	return strings.ToLower(t.Name())
 }
 
-func objectHash(obj metav1.Object) string {
+// objectHash identifies an object by kind, namespace and name. Objects that
+// only have a generateName are numbered by their ordinal (starting at 1) to
+// tell apart multiple objects with the same generateName.
+func objectHash(obj metav1.Object, ordinal int) string {
 	kind := objectKind(obj)
 
+	// "*" cannot be part of a name, so objects that only have a generateName
+	// cannot collide with named ones
+	name := obj.GetName()
+	if name == "" {
+		name = obj.GetGenerateName() + "*"
+		if ordinal > 1 {
+			name += strconv.Itoa(ordinal)
+		}
+	}
+
 	if ns := obj.GetNamespace(); ns != "" {
-		return fmt.Sprintf("%s:%s:%s", kind, ns, obj.GetName())
+		return fmt.Sprintf("%s:%s:%s", kind, ns, name)
 	} else {
-		return fmt.Sprintf("%s:%s", kind, obj.GetName())
+		return fmt.Sprintf("%s:%s", kind, name)
 	}
 }
 
@@ -80,22 +182,25 @@ func certificateNode(cert certmanagerv1.Certificate) Node {
 	return Node{Certificate: &cert}
 }
 
-func certificateHash(cert certmanagerv1.Certificate) string {
-	return objectHash(&cert)
-}
-
 func issuerNode(issuer certmanagerv1.Issuer) Node {
 	return Node{Issuer: &issuer}
 }
 
-func issuerHash(issuer certmanagerv1.Issuer) string {
-	return objectHash(&issuer)
-}
-
 func clusterIssuerNode(clusterIssuer certmanagerv1.ClusterIssuer) Node {
 	return Node{ClusterIssuer: &clusterIssuer}
 }
 
-func clusterIssuerHash(clusterIssuer certmanagerv1.ClusterIssuer) string {
-	return objectHash(&clusterIssuer)
+// ordinals counts the objects that only have a generateName, per kind,
+// namespace and generateName.
+type ordinals map[string]int
+
+// number sets the node's ordinal, so that it gets a unique hash.
+func (o ordinals) number(n Node) Node {
+	if n.Object().GetName() == "" {
+		key := objectHash(n.Object(), 0)
+		o[key]++
+		n.ordinal = o[key]
+	}
+
+	return n
 }
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package pkiplot
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoSources is returned when no sources were given.
+var ErrNoSources = errors.New("no sources given")
+
+// UnknownFormatError is returned when no renderer is registered for a format.
+type UnknownFormatError struct {
+	Format    string
+	Available []string
+}
+
+func (e *UnknownFormatError) Error() string {
+	return fmt.Sprintf("invalid output format %q, must be one of %v", e.Format, e.Available)
+}
+
+// LoadError is returned when the sources could not be loaded. Its message
+// is the one of the underlying error.
+type LoadError struct {
+	Err error
+}
+
+func (e *LoadError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// RenderError is returned when the graph could not be rendered.
+type RenderError struct {
+	Format string
+	Err    error
+}
+
+func (e *RenderError) Error() string {
+	if e.Format == "" {
+		return fmt.Sprintf("failed to render PKI: %v", e.Err)
+	}
+
+	return fmt.Sprintf("failed to render PKI as %s: %v", e.Format, e.Err)
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
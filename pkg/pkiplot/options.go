@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package pkiplot
+
+import (
+	"go.xrstf.de/pkiplot/pkg/label"
+	"go.xrstf.de/pkiplot/pkg/loader"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Option configures Load, Render and Plot.
+type Option func(*options)
+
+type options struct {
+	loader         *loader.Options
+	clusterNS      string
+	showSecrets    bool
+	showSynthetics bool
+	labels         *label.Template
+	format         string
+	renderer       render.Renderer
+	theme          *theme.Theme
+	disabledLint   []string
+	onWarning      func(loader.Warning)
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		loader:    loader.NewDefaultOptions(),
+		clusterNS: DefaultClusterResourceNamespace,
+		format:    DefaultFormat,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func (o *options) getRenderer() (render.Renderer, error) {
+	renderer := o.renderer
+	if renderer == nil {
+		var exists bool
+
+		renderer, exists = render.Get(o.format)
+		if !exists {
+			return nil, &UnknownFormatError{Format: o.format, Available: render.All()}
+		}
+	}
+
+	if themed, ok := renderer.(render.ThemedRenderer); ok && o.theme != nil {
+		renderer = themed.WithTheme(o.theme)
+	}
+
+	if linting, ok := renderer.(render.LintingRenderer); ok && o.disabledLint != nil {
+		renderer = linting.WithDisabledLintRules(o.disabledLint)
+	}
+
+	return renderer, nil
+}
+
+// WithDefaultNamespace sets the namespace for namespace-scoped objects that
+// do not have one. Without it, such objects are considered invalid.
+func WithDefaultNamespace(namespace string) Option {
+	return func(o *options) {
+		o.loader.DefaultNamespace = namespace
+	}
+}
+
+// WithIncludedNamespaces only loads namespace-scoped objects whose namespace
+// matches any of the given glob patterns.
+func WithIncludedNamespaces(patterns ...string) Option {
+	return func(o *options) {
+		o.loader.IncludeNamespaces = append(o.loader.IncludeNamespaces, patterns...)
+	}
+}
+
+// WithExcludedNamespaces skips namespace-scoped objects whose namespace
+// matches any of the given glob patterns.
+func WithExcludedNamespaces(patterns ...string) Option {
+	return func(o *options) {
+		o.loader.ExcludeNamespaces = append(o.loader.ExcludeNamespaces, patterns...)
+	}
+}
+
+// WithLabelSelector only loads objects matching the selector.
+func WithLabelSelector(selector labels.Selector) Option {
+	return func(o *options) {
+		o.loader.LabelSelector = selector
+	}
+}
+
+// WithLenient skips invalid documents and objects instead of failing.
+func WithLenient(lenient bool) Option {
+	return func(o *options) {
+		o.loader.Lenient = lenient
+	}
+}
+
+// WithMaxDocumentSize treats YAML documents larger than size bytes as
+// invalid; 0 means no limit.
+func WithMaxDocumentSize(size int) Option {
+	return func(o *options) {
+		o.loader.MaxDocumentSize = size
+	}
+}
+
+// WithWarningHandler is called for every warning that occurs while loading.
+func WithWarningHandler(handler func(loader.Warning)) Option {
+	return func(o *options) {
+		o.onWarning = handler
+	}
+}
+
+// WithClusterResourceNamespace sets cert-manager's cluster resource namespace,
+// used to find Secrets referenced by cluster-scoped objects.
+func WithClusterResourceNamespace(namespace string) Option {
+	return func(o *options) {
+		o.clusterNS = namespace
+	}
+}
+
+// WithSecrets includes Kubernetes Secrets in the graph.
+func WithSecrets(show bool) Option {
+	return func(o *options) {
+		o.showSecrets = show
+	}
+}
+
+// WithSynthetics includes objects in the graph that are only referenced, but
+// not part of the sources.
+func WithSynthetics(show bool) Option {
+	return func(o *options) {
+		o.showSynthetics = show
+	}
+}
+
+// WithFormat selects a registered renderer by name (see render.All()).
+func WithFormat(format string) Option {
+	return func(o *options) {
+		o.format = format
+		o.renderer = nil
+	}
+}
+
+// WithRenderer uses the given renderer instead of a registered one.
+func WithRenderer(renderer render.Renderer) Option {
+	return func(o *options) {
+		o.format = ""
+		o.renderer = renderer
+	}
+}
+
+// WithLabels uses the template to render node labels instead of only
+// showing each object's name.
+func WithLabels(tmpl *label.Template) Option {
+	return func(o *options) {
+		o.labels = tmpl
+	}
+}
+
+// WithTheme styles the output using the given theme instead of
+// theme.Default. Renderers that do not support themes ignore it.
+func WithTheme(th *theme.Theme) Option {
+	return func(o *options) {
+		o.theme = th
+	}
+}
+
+// WithDisabledLintRules skips the given lint rules (see lint.RuleNames) in
+// renderers that include lint findings.
+func WithDisabledLintRules(rules ...string) Option {
+	return func(o *options) {
+		o.disabledLint = append(o.disabledLint, rules...)
+	}
+}
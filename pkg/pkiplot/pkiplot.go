@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package pkiplot is the library behind the pkiplot CLI. It loads cert-manager
+// resources from files, streams or in-memory objects, turns them into a graph
+// and renders that graph in one of the registered output formats:
+//
+//	out, err := pkiplot.Plot(ctx,
+//		[]pkiplot.Source{pkiplot.FromPath("manifests/")},
+//		pkiplot.WithDefaultNamespace("kcp"),
+//		pkiplot.WithFormat("mermaid"),
+//	)
+//
+// All built-in renderers are registered by importing this package; custom
+// renderers can be registered via render.Register or passed via WithRenderer.
+package pkiplot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"go.xrstf.de/pkiplot/pkg/loader"
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	DefaultFormat                   = "mermaid"
+	DefaultClusterResourceNamespace = "cert-manager"
+)
+
+// Source is a single input for Load and Plot.
+type Source = loader.Source
+
+// FromPath reads a file, all YAML files in a directory (recursively) or,
+// if path is "-", stdin.
+func FromPath(path string) Source {
+	return loader.FromPath(path)
+}
+
+// FromReader reads a stream of YAML or JSON documents; name is used in
+// warnings and errors.
+func FromReader(name string, r io.Reader) Source {
+	return loader.FromReader(name, r)
+}
+
+// FromObjects uses already decoded Kubernetes objects; name is used in
+// warnings and errors.
+func FromObjects(name string, objects ...runtime.Object) Source {
+	return loader.FromObjects(name, objects...)
+}
+
+// Load reads all sources and builds the PKI graph. Warnings are returned
+// and also passed to the warning handler, if one is configured.
+func Load(ctx context.Context, sources []Source, opts ...Option) (pkigraph.Graph, []loader.Warning, error) {
+	return load(ctx, sources, newOptions(opts))
+}
+
+func load(ctx context.Context, sources []Source, o *options) (pkigraph.Graph, []loader.Warning, error) {
+	if len(sources) == 0 {
+		return pkigraph.Graph{}, nil, ErrNoSources
+	}
+
+	pki, warnings, err := loader.LoadSources(ctx, sources, o.loader)
+	if err != nil {
+		return pkigraph.Graph{}, nil, &LoadError{Err: err}
+	}
+
+	if o.onWarning != nil {
+		for _, warning := range warnings {
+			o.onWarning(warning)
+		}
+	}
+
+	graph := pkigraph.NewFromPKI(pki, pkigraph.Options{
+		ClusterResourceNamespace: o.clusterNS,
+		ShowSecrets:              o.showSecrets,
+		ShowSynthetics:           o.showSynthetics,
+	})
+
+	if o.labels != nil {
+		if err := graph.SetLabels(o.labels.Execute); err != nil {
+			return pkigraph.Graph{}, nil, fmt.Errorf("failed to render labels: %w", err)
+		}
+	}
+
+	return graph, warnings, nil
+}
+
+// Render writes the graph in the configured format to w.
+func Render(ctx context.Context, w io.Writer, graph pkigraph.Graph, opts ...Option) error {
+	return renderGraph(ctx, w, graph, newOptions(opts))
+}
+
+func renderGraph(ctx context.Context, w io.Writer, graph pkigraph.Graph, o *options) error {
+	renderer, err := o.getRenderer()
+	if err != nil {
+		return err
+	}
+
+	if err := renderer.Render(ctx, w, graph); err != nil {
+		return &RenderError{Format: o.format, Err: err}
+	}
+
+	return nil
+}
+
+// Plot loads all sources and renders the resulting PKI graph.
+func Plot(ctx context.Context, sources []Source, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+
+	// fail early before doing all the loading work
+	if _, err := o.getRenderer(); err != nil {
+		return nil, err
+	}
+
+	graph, _, err := load(ctx, sources, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := renderGraph(ctx, &buf, graph, o); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package pkiplot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	issuerManifest = `
+apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: ca
+  namespace: default
+spec:
+  selfSigned: {}
+`
+
+	certificateManifest = `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: web
+  namespace: default
+spec:
+  secretName: web-tls
+  issuerRef:
+    name: ca
+`
+)
+
+var errBroken = errors.New("broken renderer")
+
+// brokenRenderer always fails to render.
+type brokenRenderer struct{}
+
+func (brokenRenderer) Render(_ context.Context, _ io.Writer, _ pkigraph.Graph) error {
+	return errBroken
+}
+
+func (brokenRenderer) AddFlags(_ *pflag.FlagSet) {}
+
+func (brokenRenderer) ValidateFlags() error {
+	return nil
+}
+
+// cancellingReader cancels the context once the loader reads from it.
+type cancellingReader struct {
+	cancel context.CancelFunc
+	r      io.Reader
+}
+
+func (r *cancellingReader) Read(p []byte) (int, error) {
+	r.cancel()
+	return r.r.Read(p)
+}
+
+func TestPlot(t *testing.T) {
+	testcases := []struct {
+		name     string
+		sources  []Source
+		expected []string
+	}{
+		{
+			name:     "reader",
+			sources:  []Source{FromReader("test", strings.NewReader(issuerManifest+"---"+certificateManifest))},
+			expected: []string{"issuer_default_ca --> certificate_default_web"},
+		},
+		{
+			name: "objects",
+			sources: []Source{FromObjects("test",
+				&certmanagerv1.Issuer{
+					ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "default"},
+					Spec: certmanagerv1.IssuerSpec{
+						IssuerConfig: certmanagerv1.IssuerConfig{SelfSigned: &certmanagerv1.SelfSignedIssuer{}},
+					},
+				},
+				&certmanagerv1.Certificate{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+					Spec: certmanagerv1.CertificateSpec{
+						SecretName: "web-tls",
+						IssuerRef:  cmmeta.ObjectReference{Name: "ca"},
+					},
+				},
+			)},
+			expected: []string{"issuer_default_ca --> certificate_default_web"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := Plot(context.Background(), tc.sources)
+			if err != nil {
+				t.Fatalf("Failed to plot PKI: %v", err)
+			}
+
+			for _, name := range tc.expected {
+				if !strings.Contains(string(output), name) {
+					t.Errorf("Expected output to contain %q, got:\n%s", name, output)
+				}
+			}
+		})
+	}
+}
+
+func TestPlotErrors(t *testing.T) {
+	testcases := []struct {
+		name    string
+		ctx     func() context.Context
+		sources []Source
+		opts    []Option
+		check   func(t *testing.T, err error)
+	}{
+		{
+			name: "no sources",
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, ErrNoSources) {
+					t.Fatalf("Expected ErrNoSources, got %v", err)
+				}
+			},
+		},
+		{
+			name:    "unknown format",
+			sources: []Source{FromReader("test", strings.NewReader(issuerManifest))},
+			opts:    []Option{WithFormat("does-not-exist")},
+			check: func(t *testing.T, err error) {
+				var formatErr *UnknownFormatError
+				if !errors.As(err, &formatErr) {
+					t.Fatalf("Expected UnknownFormatError, got %v", err)
+				}
+
+				if formatErr.Format != "does-not-exist" {
+					t.Errorf("Expected format %q, got %q", "does-not-exist", formatErr.Format)
+				}
+
+				if len(formatErr.Available) == 0 {
+					t.Error("Expected available formats to be listed.")
+				}
+			},
+		},
+		{
+			name:    "invalid manifest",
+			sources: []Source{FromReader("test", strings.NewReader("kind: ["))},
+			check: func(t *testing.T, err error) {
+				var loadErr *LoadError
+				if !errors.As(err, &loadErr) {
+					t.Fatalf("Expected LoadError, got %v", err)
+				}
+
+				if loadErr.Error() != loadErr.Err.Error() {
+					t.Errorf("Expected LoadError to use the message of the underlying error, got %q", loadErr.Error())
+				}
+			},
+		},
+		{
+			name:    "failing renderer",
+			sources: []Source{FromReader("test", strings.NewReader(issuerManifest))},
+			opts:    []Option{WithRenderer(brokenRenderer{})},
+			check: func(t *testing.T, err error) {
+				var renderErr *RenderError
+				if !errors.As(err, &renderErr) {
+					t.Fatalf("Expected RenderError, got %v", err)
+				}
+
+				if !errors.Is(err, errBroken) {
+					t.Errorf("Expected RenderError to wrap the renderer's error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "cancelled before loading",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx
+			},
+			sources: []Source{FromReader("test", strings.NewReader(issuerManifest))},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, context.Canceled) {
+					t.Fatalf("Expected context.Canceled, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.ctx != nil {
+				ctx = tc.ctx()
+			}
+
+			_, err := Plot(ctx, tc.sources, tc.opts...)
+			if err == nil {
+				t.Fatal("Expected an error, but got none.")
+			}
+
+			tc.check(t, err)
+		})
+	}
+}
+
+func TestLoadStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The second reader cancels the context while the loader is busy; the
+	// broken document after it must never be parsed.
+	source := io.MultiReader(
+		strings.NewReader(issuerManifest+"---\n"),
+		&cancellingReader{cancel: cancel, r: strings.NewReader(certificateManifest + "---\nkind: [\n")},
+	)
+
+	_, _, err := Load(ctx, []Source{FromReader("test", source)})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("Expected LoadError, got %v", err)
+	}
+}
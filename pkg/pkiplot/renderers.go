@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package pkiplot
+
+import (
+	_ "go.xrstf.de/pkiplot/pkg/render/cypher"
+	_ "go.xrstf.de/pkiplot/pkg/render/cytoscape"
+	_ "go.xrstf.de/pkiplot/pkg/render/d2"
+	_ "go.xrstf.de/pkiplot/pkg/render/drawio"
+	_ "go.xrstf.de/pkiplot/pkg/render/gexf"
+	_ "go.xrstf.de/pkiplot/pkg/render/graphml"
+	_ "go.xrstf.de/pkiplot/pkg/render/graphviz"
+	_ "go.xrstf.de/pkiplot/pkg/render/json"
+	_ "go.xrstf.de/pkiplot/pkg/render/markdown"
+	_ "go.xrstf.de/pkiplot/pkg/render/mermaid"
+	_ "go.xrstf.de/pkiplot/pkg/render/plantuml"
+	_ "go.xrstf.de/pkiplot/pkg/render/png"
+	_ "go.xrstf.de/pkiplot/pkg/render/svg"
+	_ "go.xrstf.de/pkiplot/pkg/render/tree"
+)
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package attributes flattens nodes and edges into typed key/value pairs,
+// for renderers targeting generic graph formats.
+package attributes
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type Type string
+
+const (
+	String  Type = "string"
+	Boolean Type = "boolean"
+)
+
+type Attribute struct {
+	Name string
+	Type Type
+}
+
+// NodeAttributes are all attributes that nodes can have, in a stable order.
+var NodeAttributes = []Attribute{
+	{Name: "kind", Type: String},
+	{Name: "namespace", Type: String},
+	{Name: "name", Type: String},
+	{Name: "labels", Type: String},
+	{Name: "synthetic", Type: Boolean},
+	{Name: "isCA", Type: Boolean},
+	{Name: "issuerType", Type: String},
+	{Name: "secretName", Type: String},
+	{Name: "dnsNames", Type: String},
+	{Name: "duration", Type: String},
+	{Name: "notAfter", Type: String},
+}
+
+// EdgeAttributes are all attributes that edges can have.
+var EdgeAttributes = []Attribute{
+	{Name: "type", Type: String},
+}
+
+// Node returns all non-empty attributes of a node. Lists and maps are
+// joined into comma-separated strings, as most graph formats do not support
+// them.
+func Node(n pkigraph.Node) map[string]string {
+	obj := n.Object()
+
+	values := map[string]string{
+		"kind":      n.Kind(),
+		"name":      n.Name(),
+		"synthetic": strconv.FormatBool(n.Synthetic),
+		"isCA":      strconv.FormatBool(n.IsCA()),
+	}
+
+	set := func(key, value string) {
+		if value != "" {
+			values[key] = value
+		}
+	}
+
+	set("namespace", obj.GetNamespace())
+	set("issuerType", n.IssuerType())
+
+	var labels []string
+	for _, key := range sets.List(sets.KeySet(obj.GetLabels())) {
+		labels = append(labels, key+"="+obj.GetLabels()[key])
+	}
+	set("labels", strings.Join(labels, ","))
+
+	if cert := n.Certificate; cert != nil {
+		set("secretName", cert.Spec.SecretName)
+		set("dnsNames", strings.Join(cert.Spec.DNSNames, ","))
+
+		if cert.Spec.Duration != nil {
+			set("duration", cert.Spec.Duration.Duration.String())
+		}
+
+		// only known if the manifests were exported from a live cluster
+		if cert.Status.NotAfter != nil {
+			set("notAfter", cert.Status.NotAfter.UTC().Format(time.RFC3339))
+		}
+	}
+
+	return values
+}
+
+// Edge returns the attributes of the edge from a dependent node to the
+// node it depends on.
+func Edge(dependent, dependency pkigraph.Node) map[string]string {
+	return map[string]string{
+		"type": string(pkigraph.EdgeTypeOf(dependent, dependency)),
+	}
+}
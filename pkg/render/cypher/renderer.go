@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package cypher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/types"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type Options struct {
+	// Cluster is stored on every node and is part of its identity, so that
+	// PKIs from multiple clusters can be loaded into the same database.
+	Cluster string
+}
+
+type renderer struct {
+	opt Options
+}
+
+var _ render.Renderer = &renderer{}
+
+func New(opt Options) *renderer {
+	return &renderer{opt: opt}
+}
+
+var relationshipTypes = map[pkigraph.EdgeType]string{
+	pkigraph.EdgeIssues:  "ISSUES",
+	pkigraph.EdgeCreates: "CREATES",
+	pkigraph.EdgeCAFor:   "CA_FOR",
+}
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&r.opt.Cluster, "cypher-cluster", "", r.opt.Cluster, "Cypher: cluster name to store on all nodes, to distinguish multiple clusters in the same database")
+}
+
+func (r *renderer) ValidateFlags() error {
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	buf := types.NewErrWriter(w)
+
+	// sort nodes alphabetically for stable output order
+	nodeNames := sets.List(sets.KeySet(amap))
+
+	// first create all the nodes
+	for _, nodeHash := range nodeNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		node, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		buf.Printf("MERGE (n:%s %s)\n", node.Kind(), r.identity(node))
+
+		// Synthetic nodes must not overwrite the properties of nodes that
+		// were actually found, e.g. when loading multiple files.
+		if node.Synthetic {
+			buf.WriteString("ON CREATE SET n.synthetic = true;\n")
+			continue
+		}
+
+		buf.Printf("SET %s;\n", strings.Join(properties("n", node), ", "))
+	}
+
+	buf.WriteString("\n")
+
+	// then all the relationships between them
+	for _, nodeHash := range nodeNames {
+		srcNode, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		for _, destNodeHash := range sets.List(sets.KeySet(amap[nodeHash])) {
+			destNode, err := pki.Raw().Vertex(destNodeHash)
+			if err != nil {
+				return fmt.Errorf("inconsistent graph: %w", err)
+			}
+
+			// To have the relationships read naturally (issuer ISSUES certificate), we reverse the edge direction here.
+			buf.Printf("MATCH (a:%s %s), (b:%s %s)\n", destNode.Kind(), r.identity(destNode), srcNode.Kind(), r.identity(srcNode))
+			buf.Printf("MERGE (a)-[:%s]->(b);\n", relationshipTypes[pkigraph.EdgeTypeOf(srcNode, destNode)])
+		}
+	}
+
+	return buf.Err()
+}
+
+// identity returns the properties that uniquely identify a node.
+func (r *renderer) identity(node pkigraph.Node) string {
+	var props []string
+
+	if r.opt.Cluster != "" {
+		props = append(props, "cluster: "+quote(r.opt.Cluster))
+	}
+
+	if ns := node.Object().GetNamespace(); ns != "" {
+		props = append(props, "namespace: "+quote(ns))
+	}
+
+	props = append(props, "name: "+quote(node.Name()))
+
+	return "{" + strings.Join(props, ", ") + "}"
+}
+
+func properties(variable string, node pkigraph.Node) []string {
+	obj := node.Object()
+
+	props := []string{
+		variable + ".synthetic = false",
+		variable + ".isCA = " + strconv.FormatBool(node.IsCA()),
+	}
+
+	set := func(key, value string) {
+		props = append(props, fmt.Sprintf("%s.%s = %s", variable, key, value))
+	}
+
+	if issuerType := node.IssuerType(); issuerType != "" {
+		set("issuerType", quote(issuerType))
+	}
+
+	var labels []string
+	for _, key := range sets.List(sets.KeySet(obj.GetLabels())) {
+		labels = append(labels, key+"="+obj.GetLabels()[key])
+	}
+	set("labels", quoteList(labels))
+
+	if cert := node.Certificate; cert != nil {
+		set("secretName", quote(cert.Spec.SecretName))
+		set("dnsNames", quoteList(cert.Spec.DNSNames))
+
+		if cert.Spec.Duration != nil {
+			set("duration", quote(cert.Spec.Duration.Duration.String()))
+		}
+	}
+
+	return props
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func quote(s string) string {
+	return "'" + escaper.Replace(s) + "'"
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quote(value)
+	}
+
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
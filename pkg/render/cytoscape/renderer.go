@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package cytoscape
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	pkijson "go.xrstf.de/pkiplot/pkg/render/json"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type Options struct {
+	// NamespaceParents adds a compound node per namespace, which contains
+	// all nodes within that namespace.
+	NamespaceParents bool
+}
+
+type renderer struct {
+	opt Options
+}
+
+var _ render.Renderer = &renderer{}
+
+func New(opt Options) *renderer {
+	return &renderer{opt: opt}
+}
+
+// Elements can be passed as-is as the elements of a Cytoscape.js graph.
+type Elements struct {
+	Nodes []NodeElement `json:"nodes"`
+	Edges []EdgeElement `json:"edges"`
+}
+
+type NodeElement struct {
+	Data    NodeData `json:"data"`
+	Classes string   `json:"classes,omitempty"`
+}
+
+// NodeData contains the same fields as the JSON renderer's nodes, plus a
+// label and the parent compound node.
+type NodeData struct {
+	pkijson.Node
+
+	Label  string `json:"label"`
+	Parent string `json:"parent,omitempty"`
+}
+
+type EdgeElement struct {
+	Data EdgeData `json:"data"`
+}
+
+// EdgeData points from the issuing/providing node to the dependent node.
+type EdgeData struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+}
+
+const (
+	namespaceClass    = "namespace"
+	namespaceIDPrefix = "namespace:"
+)
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVarP(&r.opt.NamespaceParents, "cytoscape-namespace-parents", "", r.opt.NamespaceParents, "Cytoscape: group nodes into a compound node per namespace")
+}
+
+func (r *renderer) ValidateFlags() error {
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	elements := Elements{
+		Nodes: []NodeElement{},
+		Edges: []EdgeElement{},
+	}
+
+	namespaces := sets.New[string]()
+
+	// sort nodes alphabetically for stable output order
+	for _, nodeHash := range sets.List(sets.KeySet(amap)) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		srcNode, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		data := NodeData{
+			Node:  pkijson.NewNode(srcNode),
+			Label: strings.Join(pki.Label(srcNode), "\n"),
+		}
+
+		if ns := data.Namespace; ns != "" && r.opt.NamespaceParents {
+			data.Parent = namespaceIDPrefix + ns
+			namespaces.Insert(ns)
+		}
+
+		elements.Nodes = append(elements.Nodes, NodeElement{
+			Data:    data,
+			Classes: nodeClass(srcNode),
+		})
+
+		for _, destNodeHash := range sets.List(sets.KeySet(amap[nodeHash])) {
+			destNode, err := pki.Raw().Vertex(destNodeHash)
+			if err != nil {
+				return fmt.Errorf("inconsistent graph: %w", err)
+			}
+
+			// reverse the edges, so they point from issuer to issued
+			elements.Edges = append(elements.Edges, EdgeElement{
+				Data: EdgeData{
+					ID:     "edge:" + strconv.Itoa(len(elements.Edges)),
+					Source: destNodeHash,
+					Target: nodeHash,
+					Type:   string(pkigraph.EdgeTypeOf(srcNode, destNode)),
+				},
+			})
+		}
+	}
+
+	// parent nodes must be known before their children are added
+	var parents []NodeElement
+	for _, ns := range sets.List(namespaces) {
+		parents = append(parents, NodeElement{
+			Data: NodeData{
+				Node:  pkijson.Node{ID: namespaceIDPrefix + ns, Kind: "Namespace", Name: ns},
+				Label: ns,
+			},
+			Classes: namespaceClass,
+		})
+	}
+	elements.Nodes = append(parents, elements.Nodes...)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(elements)
+}
+
+// nodeClass returns the same classes as used by the Mermaid renderer, e.g.
+// "issuer issuer_ca synthetic".
+func nodeClass(n pkigraph.Node) string {
+	return strings.Join(theme.NodeClasses(n), " ")
+}
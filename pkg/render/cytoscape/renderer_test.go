@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package cytoscape
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	acmev1 "github.com/cert-manager/cert-manager/pkg/apis/acme/v1"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/render/mermaid"
+	"go.xrstf.de/pkiplot/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	mermaidNode  = regexp.MustCompile(`^\t(\w+)\W+"([^"]*)"\W+:::(\w+)$`)
+	mermaidClass = regexp.MustCompile(`^\tclass ([\w,]+) (\w+)$`)
+)
+
+// mermaidClasses returns the classes of every node in a Mermaid diagram,
+// keyed by the node label.
+func mermaidClasses(diagram string) map[string][]string {
+	labels := map[string]string{}
+	classes := map[string][]string{}
+
+	for _, line := range strings.Split(diagram, "\n") {
+		if match := mermaidNode.FindStringSubmatch(line); match != nil {
+			labels[match[1]] = match[2]
+			classes[match[2]] = append(classes[match[2]], match[3])
+		}
+
+		if match := mermaidClass.FindStringSubmatch(line); match != nil {
+			for _, id := range strings.Split(match[1], ",") {
+				classes[labels[id]] = append(classes[labels[id]], match[2])
+			}
+		}
+	}
+
+	return classes
+}
+
+func TestNodeClassesMatchMermaid(t *testing.T) {
+	pki := &types.PKI{
+		Issuers: []certmanagerv1.Issuer{{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca-issuer", Namespace: "default"},
+			Spec: certmanagerv1.IssuerSpec{
+				IssuerConfig: certmanagerv1.IssuerConfig{CA: &certmanagerv1.CAIssuer{SecretName: "ca"}},
+			},
+		}},
+		ClusterIssuers: []certmanagerv1.ClusterIssuer{{
+			ObjectMeta: metav1.ObjectMeta{Name: "letsencrypt"},
+			Spec: certmanagerv1.IssuerSpec{
+				IssuerConfig: certmanagerv1.IssuerConfig{ACME: &acmev1.ACMEIssuer{Server: "https://acme.example.com"}},
+			},
+		}},
+		Certificates: []certmanagerv1.Certificate{{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: certmanagerv1.CertificateSpec{
+				SecretName: "web-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+			},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "default"},
+			Spec: certmanagerv1.CertificateSpec{
+				SecretName: "internal-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "missing"},
+			},
+		}},
+	}
+
+	graph := pkigraph.NewFromPKI(pki, pkigraph.Options{ShowSynthetics: true})
+
+	diagram, err := render.ToString(context.Background(), mermaid.New(mermaid.Options{}), graph)
+	if err != nil {
+		t.Fatalf("Failed to render Mermaid diagram: %v", err)
+	}
+
+	expected := mermaidClasses(diagram)
+
+	rendered, err := render.ToString(context.Background(), New(Options{}), graph)
+	if err != nil {
+		t.Fatalf("Failed to render Cytoscape elements: %v", err)
+	}
+
+	var elements Elements
+	if err := json.Unmarshal([]byte(rendered), &elements); err != nil {
+		t.Fatalf("Failed to parse Cytoscape elements: %v", err)
+	}
+
+	if len(elements.Nodes) != len(expected) {
+		t.Fatalf("Expected %d nodes, got %d", len(expected), len(elements.Nodes))
+	}
+
+	for _, node := range elements.Nodes {
+		classes := strings.Fields(node.Classes)
+		slices.Sort(classes)
+
+		mermaidClasses := expected[node.Data.Name]
+		slices.Sort(mermaidClasses)
+
+		if !slices.Equal(classes, mermaidClasses) {
+			t.Errorf("%s: expected classes %v, got %v", node.Data.ID, mermaidClasses, classes)
+		}
+	}
+
+	// make sure the test actually covers issuer types and states
+	for name, class := range map[string]string{"ca-issuer": "issuer_ca", "letsencrypt": "issuer_acme", "missing": "synthetic"} {
+		if !slices.Contains(expected[name], class) {
+			t.Errorf("Expected %s to have class %s, got %v", name, class, expected[name])
+		}
+	}
+}
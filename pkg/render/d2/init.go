@@ -0,0 +1,12 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package d2
+
+import (
+	"go.xrstf.de/pkiplot/pkg/render"
+)
+
+func init() {
+	render.Register("d2", New(Options{}))
+}
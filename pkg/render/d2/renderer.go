@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package d2
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/theme"
+	"go.xrstf.de/pkiplot/pkg/types"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type Options struct {
+	// DisableEdgeLabels skips the labels describing each edge's type.
+	DisableEdgeLabels bool
+	// Theme styles the output; defaults to theme.Default.
+	Theme *theme.Theme
+}
+
+type renderer struct {
+	opt Options
+}
+
+var _ render.ThemedRenderer = &renderer{}
+
+func New(opt Options) *renderer {
+	if opt.Theme == nil {
+		opt.Theme = theme.Default
+	}
+
+	return &renderer{opt: opt}
+}
+
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	opt := r.opt
+	opt.Theme = th
+
+	return New(opt)
+}
+
+// classes are output in this order, each with a shape fitting the kind.
+var classes = []struct {
+	name  string
+	shape string
+}{
+	{"clusterissuer", "hexagon"},
+	{"issuer", "hexagon"},
+	{"ca", "page"},
+	{"certificate", "page"},
+	{"secret", "stored_data"},
+}
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVarP(&r.opt.DisableEdgeLabels, "d2-disable-edge-labels", "", r.opt.DisableEdgeLabels, "D2: do not label edges with their type")
+}
+
+func (r *renderer) ValidateFlags() error {
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	buf := types.NewErrWriter(w)
+	buf.WriteString("direction: down\n\n")
+
+	if err := r.writeClasses(buf); err != nil {
+		return err
+	}
+
+	// group nodes by namespace, cluster-scoped nodes are not in any container
+	namespaces := map[string][]pkigraph.Node{}
+
+	// sort nodes alphabetically for stable output order
+	nodeNames := sets.List(sets.KeySet(amap))
+
+	for _, nodeHash := range nodeNames {
+		node, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		ns := node.Object().GetNamespace()
+		namespaces[ns] = append(namespaces[ns], node)
+	}
+
+	for _, node := range namespaces[""] {
+		if err := r.writeNode(buf, node, pki.Label(node), ""); err != nil {
+			return err
+		}
+	}
+
+	for _, ns := range sets.List(sets.KeySet(namespaces)) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if ns == "" {
+			continue
+		}
+
+		buf.Printf("\n%s: {\n", strconv.Quote(ns))
+		buf.Printf("\tlabel: %s\n", strconv.Quote("namespace "+ns))
+		for _, node := range namespaces[ns] {
+			if err := r.writeNode(buf, node, pki.Label(node), "\t"); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}\n")
+	}
+
+	buf.WriteString("\n")
+
+	for _, nodeHash := range nodeNames {
+		srcNode, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		for _, destNodeHash := range sets.List(sets.KeySet(amap[nodeHash])) {
+			destNode, err := pki.Raw().Vertex(destNodeHash)
+			if err != nil {
+				return fmt.Errorf("inconsistent graph: %w", err)
+			}
+
+			// To have the chart be readable from top to bottom, we reverse the edge direction here.
+			buf.Printf("%s -> %s", nodePath(destNode), nodePath(srcNode))
+			if !r.opt.DisableEdgeLabels {
+				buf.Printf(": %s", strconv.Quote(string(pkigraph.EdgeTypeOf(srcNode, destNode))))
+			}
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.Err()
+}
+
+func (r *renderer) writeClasses(buf *types.ErrWriter) error {
+	buf.WriteString("classes: {\n")
+
+	for _, class := range classes {
+		style := r.opt.Theme.ClassStyle(class.name)
+
+		shape := class.shape
+		if themeShape, ok := themeShapes[style.Shape]; ok {
+			shape = themeShape
+		}
+
+		buf.Printf("\t%s: {\n", class.name)
+		buf.Printf("\t\tshape: %s\n", shape)
+		if err := writeStyle(buf, style, "\t\t"); err != nil {
+			return err
+		}
+		buf.WriteString("\t}\n")
+	}
+
+	buf.WriteString("}\n\n")
+
+	return nil
+}
+
+func (r *renderer) writeNode(buf *types.ErrWriter, node pkigraph.Node, label []string, indent string) error {
+	buf.Printf("%s%s: {\n", indent, nodeKey(node))
+	buf.Printf("%s\tlabel: %s\n", indent, strconv.Quote(strings.Join(label, "\n")))
+	buf.Printf("%s\tclass: %s\n", indent, node.Class())
+
+	// override the class style, e.g. because of the node's state
+	th := r.opt.Theme
+	classStyle := th.ClassStyle(node.Class())
+
+	if style := th.NodeStyle(node); style != classStyle {
+		if shape, ok := themeShapes[style.Shape]; ok && style.Shape != classStyle.Shape {
+			buf.Printf("%s\tshape: %s\n", indent, shape)
+		}
+
+		if err := writeStyle(buf, style, indent+"\t"); err != nil {
+			return err
+		}
+	}
+
+	buf.Printf("%s}\n", indent)
+
+	return nil
+}
+
+// themeShapes map theme shapes to D2 shapes.
+var themeShapes = map[string]string{
+	theme.ShapeStadium:   "oval",
+	theme.ShapeRectangle: "rectangle",
+	theme.ShapeRounded:   "rectangle",
+	theme.ShapeHexagon:   "hexagon",
+	theme.ShapeCircle:    "circle",
+}
+
+func writeStyle(buf *types.ErrWriter, style theme.Style, indent string) error {
+	col, fill, err := style.Colors()
+	if err != nil {
+		return err
+	}
+
+	buf.Printf("%sstyle.fill: \"%s\"\n", indent, theme.Hex(fill))
+	buf.Printf("%sstyle.stroke: \"%s\"\n", indent, theme.Hex(col))
+
+	if style.Shape == theme.ShapeRounded {
+		buf.Printf("%sstyle.border-radius: 8\n", indent)
+	}
+
+	if style.Dashed {
+		buf.Printf("%sstyle.stroke-dash: 3\n", indent)
+	}
+
+	return nil
+}
+
+// nodeKey is unique within a container, as nodes of different kinds can
+// share the same name.
+func nodeKey(node pkigraph.Node) string {
+	return strconv.Quote(node.ObjectKind() + ":" + node.Name())
+}
+
+// nodePath is the fully qualified key, including the namespace container.
+func nodePath(node pkigraph.Node) string {
+	if ns := node.Object().GetNamespace(); ns != "" {
+		return strconv.Quote(ns) + "." + nodeKey(node)
+	}
+
+	return nodeKey(node)
+}
@@ -0,0 +1,12 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package drawio
+
+import (
+	"go.xrstf.de/pkiplot/pkg/render"
+)
+
+func init() {
+	render.Register("drawio", New(Options{}))
+}
@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package drawio
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/diagram"
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type Options struct {
+	// ShowType includes a node's type in its label.
+	ShowType bool
+	// Theme styles the output; defaults to theme.Default.
+	Theme *theme.Theme
+}
+
+type renderer struct {
+	opt Options
+}
+
+var _ render.ThemedRenderer = &renderer{}
+
+func New(opt Options) *renderer {
+	if opt.Theme == nil {
+		opt.Theme = theme.Default
+	}
+
+	return &renderer{opt: opt}
+}
+
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	opt := r.opt
+	opt.Theme = th
+
+	return New(opt)
+}
+
+// shapes are the draw.io styles per node class, colors are added separately.
+var shapes = map[string]string{
+	"clusterissuer": "shape=hexagon;perimeter=hexagonPerimeter2;size=12;fixedSize=1;",
+	"issuer":        "shape=hexagon;perimeter=hexagonPerimeter2;size=12;fixedSize=1;",
+	"ca":            "rounded=1;arcSize=50;fontStyle=1;",
+	"certificate":   "rounded=1;arcSize=50;",
+	"secret":        "shape=cylinder3;boundedLbl=1;size=6;",
+}
+
+// themeShapes are used instead of the class shapes if the theme sets a shape.
+var themeShapes = map[string]string{
+	theme.ShapeStadium:   "rounded=1;arcSize=50;",
+	theme.ShapeRectangle: "rounded=0;",
+	theme.ShapeRounded:   "rounded=1;",
+	theme.ShapeHexagon:   "shape=hexagon;perimeter=hexagonPerimeter2;size=12;fixedSize=1;",
+	theme.ShapeCircle:    "ellipse;",
+}
+
+const (
+	overviewPage = "Overview"
+	// foreignOpacity is used for nodes from other namespaces that are shown
+	// on a namespace's page because they are connected to it.
+	foreignOpacity = 40
+)
+
+type mxFile struct {
+	XMLName  xml.Name    `xml:"mxfile"`
+	Host     string      `xml:"host,attr"`
+	Diagrams []mxDiagram `xml:"diagram"`
+}
+
+type mxDiagram struct {
+	ID    string       `xml:"id,attr"`
+	Name  string       `xml:"name,attr"`
+	Model mxGraphModel `xml:"mxGraphModel"`
+}
+
+type mxGraphModel struct {
+	Grid       int      `xml:"grid,attr"`
+	GridSize   int      `xml:"gridSize,attr"`
+	Page       int      `xml:"page,attr"`
+	PageWidth  int      `xml:"pageWidth,attr"`
+	PageHeight int      `xml:"pageHeight,attr"`
+	Cells      []mxCell `xml:"root>mxCell"`
+}
+
+type mxCell struct {
+	ID       string      `xml:"id,attr"`
+	Value    string      `xml:"value,attr,omitempty"`
+	Style    string      `xml:"style,attr,omitempty"`
+	Vertex   string      `xml:"vertex,attr,omitempty"`
+	Edge     string      `xml:"edge,attr,omitempty"`
+	Parent   string      `xml:"parent,attr,omitempty"`
+	Source   string      `xml:"source,attr,omitempty"`
+	Target   string      `xml:"target,attr,omitempty"`
+	Geometry *mxGeometry `xml:"mxGeometry,omitempty"`
+}
+
+type mxGeometry struct {
+	X        string   `xml:"x,attr,omitempty"`
+	Y        string   `xml:"y,attr,omitempty"`
+	Width    string   `xml:"width,attr,omitempty"`
+	Height   string   `xml:"height,attr,omitempty"`
+	Relative string   `xml:"relative,attr,omitempty"`
+	As       string   `xml:"as,attr"`
+	Points   *mxArray `xml:"Array,omitempty"`
+}
+
+type mxArray struct {
+	As     string    `xml:"as,attr"`
+	Points []mxPoint `xml:"mxPoint"`
+}
+
+type mxPoint struct {
+	X string `xml:"x,attr"`
+	Y string `xml:"y,attr"`
+}
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVarP(&r.opt.ShowType, "drawio-show-type", "", r.opt.ShowType, "draw.io: include a node's type in the node label")
+}
+
+func (r *renderer) ValidateFlags() error {
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	namespaces := sets.New[string]()
+	for nodeHash := range amap {
+		node, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		if ns := node.Object().GetNamespace(); ns != "" {
+			namespaces.Insert(ns)
+		}
+	}
+
+	file := mxFile{Host: "pkiplot"}
+
+	overview, err := r.page(pki, overviewPage, "")
+	if err != nil {
+		return err
+	}
+	file.Diagrams = append(file.Diagrams, *overview)
+
+	predecessors, err := pki.Raw().PredecessorMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	for _, ns := range sets.List(namespaces) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// include all nodes in the namespace and their direct neighbours
+		included := sets.New[string]()
+		for nodeHash := range amap {
+			node, _ := pki.Raw().Vertex(nodeHash)
+			if node.Object().GetNamespace() != ns {
+				continue
+			}
+
+			included.Insert(nodeHash)
+			included.Insert(sets.List(sets.KeySet(amap[nodeHash]))...)
+			included.Insert(sets.List(sets.KeySet(predecessors[nodeHash]))...)
+		}
+
+		sub, err := pki.Subgraph(func(n pkigraph.Node) bool {
+			return included.Has(n.Hash())
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create subgraph: %w", err)
+		}
+
+		page, err := r.page(sub, ns, ns)
+		if err != nil {
+			return err
+		}
+
+		file.Diagrams = append(file.Diagrams, *page)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(file); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// page lays out the graph; if namespace is given, nodes in other
+// namespaces are faded out.
+func (r *renderer) page(pki pkigraph.Graph, name string, namespace string) (*mxDiagram, error) {
+	opt := diagram.NewDefaultOptions()
+	opt.ShowType = r.opt.ShowType
+	opt.Theme = r.opt.Theme
+
+	scene, err := diagram.Build(pki, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &mxDiagram{
+		ID:   "page-" + name,
+		Name: name,
+		Model: mxGraphModel{
+			Grid:       1,
+			GridSize:   10,
+			Page:       1,
+			PageWidth:  int(scene.Width + 0.5),
+			PageHeight: int(scene.Height + 0.5),
+			Cells: []mxCell{
+				{ID: "0"},
+				{ID: "1", Parent: "0"},
+			},
+		},
+	}
+
+	for _, node := range scene.Nodes {
+		style, err := nodeStyle(node)
+		if err != nil {
+			return nil, err
+		}
+
+		if namespace != "" {
+			if n, err := pki.Raw().Vertex(node.ID); err == nil && n.Object().GetNamespace() != namespace {
+				style += fmt.Sprintf("opacity=%d;textOpacity=%d;", foreignOpacity, foreignOpacity)
+			}
+		}
+
+		page.Model.Cells = append(page.Model.Cells, mxCell{
+			ID:     node.ID,
+			Value:  nodeLabel(node),
+			Style:  style,
+			Vertex: "1",
+			Parent: "1",
+			Geometry: &mxGeometry{
+				X:      num(node.X),
+				Y:      num(node.Y),
+				Width:  num(node.Width),
+				Height: num(node.Height),
+				As:     "geometry",
+			},
+		})
+	}
+
+	for i, edge := range scene.Edges {
+		col, err := theme.ParseColor(edge.Color)
+		if err != nil {
+			return nil, err
+		}
+
+		style := fmt.Sprintf("html=1;curved=1;endArrow=block;endFill=1;strokeColor=%s;", theme.Hex(col))
+		if edge.Dashed {
+			style += "dashed=1;"
+		}
+
+		geometry := &mxGeometry{Relative: "1", As: "geometry"}
+
+		// the first and last points are on the nodes, draw.io determines those
+		if len(edge.Points) > 2 {
+			geometry.Points = &mxArray{As: "points"}
+			for _, p := range edge.Points[1 : len(edge.Points)-1] {
+				geometry.Points.Points = append(geometry.Points.Points, mxPoint{X: num(p.X), Y: num(p.Y)})
+			}
+		}
+
+		page.Model.Cells = append(page.Model.Cells, mxCell{
+			ID:       fmt.Sprintf("edge-%d", i),
+			Style:    style,
+			Edge:     "1",
+			Parent:   "1",
+			Source:   edge.From,
+			Target:   edge.To,
+			Geometry: geometry,
+		})
+	}
+
+	return page, nil
+}
+
+func nodeStyle(node diagram.Node) (string, error) {
+	textColor, err := theme.ParseColor(diagram.TextColor)
+	if err != nil {
+		return "", err
+	}
+
+	shape, ok := themeShapes[node.Shape]
+	if !ok {
+		shape = shapes[node.Class]
+	}
+
+	style := shape + "whiteSpace=wrap;html=1;strokeWidth=2;"
+	style += fmt.Sprintf("fillColor=%s;strokeColor=%s;fontColor=%s;", node.Fill, node.Color, theme.Hex(textColor))
+
+	if node.Dashed {
+		style += "dashed=1;"
+	}
+
+	return style, nil
+}
+
+// nodeLabel returns HTML, as the nodes use html=1.
+func nodeLabel(node diagram.Node) string {
+	lines := make([]string, len(node.Lines))
+	for i, line := range node.Lines {
+		lines[i] = html.EscapeString(line)
+	}
+
+	return strings.Join(lines, "<br>")
+}
+
+// num rounds coordinates to two decimals.
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,12 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package gexf
+
+import (
+	"go.xrstf.de/pkiplot/pkg/render"
+)
+
+func init() {
+	render.Register("gexf", New())
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package gexf
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/render/attributes"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type renderer struct {
+	theme *theme.Theme
+}
+
+var _ render.ThemedRenderer = &renderer{}
+
+func New() *renderer {
+	return &renderer{theme: theme.Default}
+}
+
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	return &renderer{theme: th}
+}
+
+type document struct {
+	XMLName xml.Name `xml:"gexf"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	VizNS   string   `xml:"xmlns:viz,attr"`
+	Version string   `xml:"version,attr"`
+	Meta    meta     `xml:"meta"`
+	Graph   graph    `xml:"graph"`
+}
+
+type meta struct {
+	Creator string `xml:"creator"`
+}
+
+type graph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr"`
+	Attributes      []attributeClass `xml:"attributes"`
+	Nodes           []node           `xml:"nodes>node"`
+	Edges           []edge           `xml:"edges>edge"`
+}
+
+type attributeClass struct {
+	Class      string      `xml:"class,attr"`
+	Attributes []attribute `xml:"attribute"`
+}
+
+type attribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type node struct {
+	ID        string     `xml:"id,attr"`
+	Label     string     `xml:"label,attr"`
+	AttValues []attValue `xml:"attvalues>attvalue"`
+	Color     color      `xml:"viz:color"`
+}
+
+type edge struct {
+	ID        string     `xml:"id,attr"`
+	Source    string     `xml:"source,attr"`
+	Target    string     `xml:"target,attr"`
+	Label     string     `xml:"label,attr"`
+	AttValues []attValue `xml:"attvalues>attvalue"`
+}
+
+type attValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type color struct {
+	R uint8 `xml:"r,attr"`
+	G uint8 `xml:"g,attr"`
+	B uint8 `xml:"b,attr"`
+}
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	// NOP
+}
+
+func (r *renderer) ValidateFlags() error {
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	doc := document{
+		XMLNS:   "http://gexf.net/1.3",
+		VizNS:   "http://gexf.net/1.3/viz",
+		Version: "1.3",
+		Meta:    meta{Creator: "pkiplot"},
+		Graph: graph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes: []attributeClass{
+				{Class: "node", Attributes: convertAttributes(attributes.NodeAttributes)},
+				{Class: "edge", Attributes: convertAttributes(attributes.EdgeAttributes)},
+			},
+		},
+	}
+
+	// sort nodes alphabetically for stable output order
+	nodeNames := sets.List(sets.KeySet(amap))
+
+	for _, nodeHash := range nodeNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		srcNode, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		col, _, err := r.theme.NodeStyle(srcNode).Colors()
+		if err != nil {
+			return err
+		}
+
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node{
+			ID:        nodeHash,
+			Label:     strings.Join(pki.Label(srcNode), "\n"),
+			AttValues: convertValues(attributes.NodeAttributes, attributes.Node(srcNode)),
+			Color:     color{R: col.R, G: col.G, B: col.B},
+		})
+
+		for _, destNodeHash := range sets.List(sets.KeySet(amap[nodeHash])) {
+			destNode, err := pki.Raw().Vertex(destNodeHash)
+			if err != nil {
+				return fmt.Errorf("inconsistent graph: %w", err)
+			}
+
+			// reverse the edges, so they point from issuer to issued
+			doc.Graph.Edges = append(doc.Graph.Edges, edge{
+				ID:        strconv.Itoa(len(doc.Graph.Edges)),
+				Source:    destNodeHash,
+				Target:    nodeHash,
+				Label:     string(pkigraph.EdgeTypeOf(srcNode, destNode)),
+				AttValues: convertValues(attributes.EdgeAttributes, attributes.Edge(srcNode, destNode)),
+			})
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func convertAttributes(attrs []attributes.Attribute) []attribute {
+	var result []attribute
+	for _, attr := range attrs {
+		result = append(result, attribute{ID: attr.Name, Title: attr.Name, Type: string(attr.Type)})
+	}
+
+	return result
+}
+
+func convertValues(attrs []attributes.Attribute, values map[string]string) []attValue {
+	var result []attValue
+	for _, attr := range attrs {
+		if value, ok := values[attr.Name]; ok {
+			result = append(result, attValue{For: attr.Name, Value: value})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package graphml
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/render/attributes"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type renderer struct {
+	theme *theme.Theme
+}
+
+var _ render.ThemedRenderer = &renderer{}
+
+func New() *renderer {
+	return &renderer{theme: theme.Default}
+}
+
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	return &renderer{theme: th}
+}
+
+// graphicsKey holds yEd's node graphics, so nodes are labelled and colored
+// when opening the file in yEd; other tools ignore it.
+const graphicsKey = "yed-graphics"
+
+type document struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	YNS     string   `xml:"xmlns:y,attr"`
+	Keys    []key    `xml:"key"`
+	Graph   graph    `xml:"graph"`
+}
+
+type key struct {
+	ID         string `xml:"id,attr"`
+	For        string `xml:"for,attr"`
+	Name       string `xml:"attr.name,attr,omitempty"`
+	Type       string `xml:"attr.type,attr,omitempty"`
+	YFilesType string `xml:"yfiles.type,attr,omitempty"`
+}
+
+type graph struct {
+	ID          string `xml:"id,attr"`
+	EdgeDefault string `xml:"edgedefault,attr"`
+	Nodes       []node `xml:"node"`
+	Edges       []edge `xml:"edge"`
+}
+
+type node struct {
+	ID   string `xml:"id,attr"`
+	Data []data `xml:"data"`
+}
+
+type edge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []data `xml:"data"`
+}
+
+type data struct {
+	Key       string     `xml:"key,attr"`
+	Value     string     `xml:",chardata"`
+	ShapeNode *shapeNode `xml:"y:ShapeNode,omitempty"`
+}
+
+type shapeNode struct {
+	Fill struct {
+		Color string `xml:"color,attr"`
+	} `xml:"y:Fill"`
+	BorderStyle struct {
+		Color string `xml:"color,attr"`
+		Type  string `xml:"type,attr"`
+		Width string `xml:"width,attr"`
+	} `xml:"y:BorderStyle"`
+	NodeLabel string `xml:"y:NodeLabel"`
+	Shape     struct {
+		Type string `xml:"type,attr"`
+	} `xml:"y:Shape"`
+}
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	// NOP
+}
+
+func (r *renderer) ValidateFlags() error {
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	doc := document{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		YNS:   "http://www.yworks.com/xml/graphml",
+		Graph: graph{
+			ID:          "pki",
+			EdgeDefault: "directed",
+		},
+	}
+
+	for _, attr := range attributes.NodeAttributes {
+		doc.Keys = append(doc.Keys, key{ID: attr.Name, For: "node", Name: attr.Name, Type: string(attr.Type)})
+	}
+
+	for _, attr := range attributes.EdgeAttributes {
+		doc.Keys = append(doc.Keys, key{ID: "edge-" + attr.Name, For: "edge", Name: attr.Name, Type: string(attr.Type)})
+	}
+
+	doc.Keys = append(doc.Keys, key{ID: graphicsKey, For: "node", YFilesType: "nodegraphics"})
+
+	// sort nodes alphabetically for stable output order
+	nodeNames := sets.List(sets.KeySet(amap))
+
+	for _, nodeHash := range nodeNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		srcNode, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		n := node{ID: nodeHash}
+
+		values := attributes.Node(srcNode)
+		for _, attr := range attributes.NodeAttributes {
+			if value, ok := values[attr.Name]; ok {
+				n.Data = append(n.Data, data{Key: attr.Name, Value: value})
+			}
+		}
+
+		graphics, err := nodeGraphics(r.theme, srcNode, pki.Label(srcNode))
+		if err != nil {
+			return err
+		}
+
+		n.Data = append(n.Data, data{Key: graphicsKey, ShapeNode: graphics})
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+
+		for _, destNodeHash := range sets.List(sets.KeySet(amap[nodeHash])) {
+			destNode, err := pki.Raw().Vertex(destNodeHash)
+			if err != nil {
+				return fmt.Errorf("inconsistent graph: %w", err)
+			}
+
+			// reverse the edges, so they point from issuer to issued
+			e := edge{Source: destNodeHash, Target: nodeHash}
+
+			values := attributes.Edge(srcNode, destNode)
+			for _, attr := range attributes.EdgeAttributes {
+				if value, ok := values[attr.Name]; ok {
+					e.Data = append(e.Data, data{Key: "edge-" + attr.Name, Value: value})
+				}
+			}
+
+			doc.Graph.Edges = append(doc.Graph.Edges, e)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// themeShapes map theme shapes to yEd shapes.
+var themeShapes = map[string]string{
+	theme.ShapeStadium:   "roundrectangle",
+	theme.ShapeRectangle: "rectangle",
+	theme.ShapeRounded:   "roundrectangle",
+	theme.ShapeHexagon:   "hexagon",
+	theme.ShapeCircle:    "ellipse",
+}
+
+func nodeGraphics(th *theme.Theme, n pkigraph.Node, label []string) (*shapeNode, error) {
+	style := th.NodeStyle(n)
+
+	col, fill, err := style.Colors()
+	if err != nil {
+		return nil, err
+	}
+
+	graphics := &shapeNode{NodeLabel: strings.Join(label, "\n")}
+	graphics.Fill.Color = theme.Hex(fill)
+	graphics.BorderStyle.Color = theme.Hex(col)
+	graphics.BorderStyle.Type = "line"
+	graphics.BorderStyle.Width = "2.0"
+	graphics.Shape.Type = "roundrectangle"
+
+	if shape, ok := themeShapes[style.Shape]; ok {
+		graphics.Shape.Type = shape
+	}
+
+	if style.Dashed {
+		graphics.BorderStyle.Type = "dashed"
+	}
+
+	return graphics, nil
+}
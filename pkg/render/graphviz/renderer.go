@@ -4,21 +4,33 @@
 package graphviz
 
 import (
-	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
 
+	"github.com/dominikbraun/graph"
 	"github.com/dominikbraun/graph/draw"
 	"github.com/spf13/pflag"
 
 	"go.xrstf.de/pkiplot/pkg/pkigraph"
 	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/theme"
 )
 
-type renderer struct{}
+type renderer struct {
+	theme *theme.Theme
+}
 
-var _ render.Renderer = &renderer{}
+var _ render.ThemedRenderer = &renderer{}
 
 func New() *renderer {
-	return &renderer{}
+	return &renderer{theme: theme.Default}
+}
+
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	return &renderer{theme: th}
 }
 
 func (r *renderer) AddFlags(fs *pflag.FlagSet) {
@@ -29,12 +41,118 @@ func (r *renderer) ValidateFlags() error {
 	return nil
 }
 
-func (r *renderer) RenderGraph(pki pkigraph.Graph) (string, error) {
-	var buf bytes.Buffer
-	err := draw.DOT(pki.Raw(), &buf)
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	styled, err := styledGraph(pki, r.theme)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return buf.String(), nil
+	return draw.DOT(styled, w)
+}
+
+// dotEscaper prepares text for draw.DOT, which does not escape attribute values.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// themeShapes map theme shapes to Graphviz shapes and additional styles.
+var themeShapes = map[string][2]string{
+	theme.ShapeStadium:   {"box", "rounded"},
+	theme.ShapeRectangle: {"box", ""},
+	theme.ShapeRounded:   {"box", "rounded"},
+	theme.ShapeHexagon:   {"hexagon", ""},
+	theme.ShapeCircle:    {"ellipse", ""},
+}
+
+// styledGraph returns a copy of the graph with DOT attributes for the theme
+// and custom labels; nodes without a custom label are labelled with their hash.
+func styledGraph(pki pkigraph.Graph, th *theme.Theme) (graph.Graph[string, pkigraph.Node], error) {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return nil, fmt.Errorf("invalid graph: %w", err)
+	}
+
+	styled := graph.NewLike(pki.Raw())
+
+	for hash := range amap {
+		node, err := pki.Raw().Vertex(hash)
+		if err != nil {
+			return nil, fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		attributes, err := nodeAttributes(th.NodeStyle(node))
+		if err != nil {
+			return nil, err
+		}
+
+		if lines := pki.Label(node); !slices.Equal(lines, []string{node.Name()}) {
+			attributes["label"] = dotEscaper.Replace(strings.Join(lines, "\n"))
+		}
+
+		if err := styled.AddVertex(node, graph.VertexAttributes(attributes)); err != nil {
+			return nil, err
+		}
+	}
+
+	for source, targets := range amap {
+		dependent, err := pki.Raw().Vertex(source)
+		if err != nil {
+			return nil, fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		for target := range targets {
+			dependency, err := pki.Raw().Vertex(target)
+			if err != nil {
+				return nil, fmt.Errorf("inconsistent graph: %w", err)
+			}
+
+			attributes := map[string]string{}
+
+			style := th.EdgeStyle(pkigraph.EdgeTypeOf(dependent, dependency))
+			if style.Color != "" {
+				col, _, err := style.Colors()
+				if err != nil {
+					return nil, err
+				}
+
+				attributes["color"] = theme.Hex(col)
+			}
+
+			if style.Dashed {
+				attributes["style"] = "dashed"
+			}
+
+			if err := styled.AddEdge(source, target, graph.EdgeAttributes(attributes)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return styled, nil
+}
+
+func nodeAttributes(style theme.Style) (map[string]string, error) {
+	col, fill, err := style.Colors()
+	if err != nil {
+		return nil, err
+	}
+
+	styles := []string{"filled"}
+	attributes := map[string]string{
+		"color":     theme.Hex(col),
+		"fillcolor": theme.Hex(fill),
+	}
+
+	if shape, ok := themeShapes[style.Shape]; ok {
+		attributes["shape"] = shape[0]
+		if shape[1] != "" {
+			styles = append(styles, shape[1])
+		}
+	}
+
+	if style.Dashed {
+		styles = append(styles, "dashed")
+	}
+
+	attributes["style"] = strings.Join(styles, ",")
+
+	return attributes, nil
 }
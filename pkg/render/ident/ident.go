@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package ident assigns unique identifiers to nodes, for renderers targeting
+// formats that only allow a limited set of characters in IDs.
+package ident
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+)
+
+// unsafeChars matches everything that cannot be used in an identifier. The
+// underscore is included, so that sanitized strings never contain "__",
+// which is used to separate the suffix of colliding identifiers.
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// Identifiers maps node hashes to identifiers.
+type Identifiers map[string]string
+
+// New assigns a unique identifier to every node. Sanitizing names is lossy
+// (e.g. "a-b" and "a.b" both become "a_b"), so nodes sharing the same
+// identifier get a numeric suffix (e.g. "a_b__2"), in the order of their
+// hashes. Suffixes only depend on the nodes sharing an identifier, so adding
+// other nodes never changes existing identifiers. Hashes must be given in a
+// stable order to keep identifiers deterministic.
+func New(pki pkigraph.Graph, hashes []string) (Identifiers, error) {
+	ids := Identifiers{}
+	seen := map[string]int{}
+
+	for _, hash := range hashes {
+		node, err := pki.Raw().Vertex(hash)
+		if err != nil {
+			return nil, fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		base := nodeID(node)
+		seen[base]++
+
+		if n := seen[base]; n > 1 {
+			ids[hash] = fmt.Sprintf("%s__%d", base, n)
+		} else {
+			ids[hash] = base
+		}
+	}
+
+	return ids, nil
+}
+
+func (ids Identifiers) Get(node pkigraph.Node) string {
+	return ids[node.Hash()]
+}
+
+func nodeID(node pkigraph.Node) string {
+	ident := node.Name()
+
+	if ns := node.Object().GetNamespace(); ns != "" {
+		ident = ns + "_" + ident
+	}
+
+	return Sanitize(node.ObjectKind() + "_" + ident)
+}
+
+// Sanitize replaces all runs of characters that are not allowed in
+// identifiers (including underscores) with a single underscore.
+func Sanitize(s string) string {
+	return unsafeChars.ReplaceAllString(s, "_")
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package json
+
+import (
+	"cmp"
+	"context"
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type renderer struct{}
+
+var _ render.Renderer = &renderer{}
+
+func New() *renderer {
+	return &renderer{}
+}
+
+// Document is the JSON representation of a PKI graph.
+type Document struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+type Node struct {
+	ID         string            `json:"id"`
+	Kind       string            `json:"kind"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Name       string            `json:"name"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Synthetic  bool              `json:"synthetic,omitempty"`
+	IsCA       bool              `json:"isCA,omitempty"`
+	IssuerType string            `json:"issuerType,omitempty"`
+	SecretName string            `json:"secretName,omitempty"`
+	DNSNames   []string          `json:"dnsNames,omitempty"`
+}
+
+// Edge points from the issuing/providing node to the dependent node, e.g.
+// from an Issuer to the Certificates it issues.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	// NOP
+}
+
+func (r *renderer) ValidateFlags() error {
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	doc, err := NewDocument(pki)
+	if err != nil {
+		return err
+	}
+
+	encoder := stdjson.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
+
+// NewDocument converts the graph into its JSON representation.
+func NewDocument(pki pkigraph.Graph) (*Document, error) {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return nil, fmt.Errorf("invalid graph: %w", err)
+	}
+
+	doc := &Document{
+		Nodes: []Node{},
+		Edges: []Edge{},
+	}
+
+	for _, nodeHash := range sets.List(sets.KeySet(amap)) {
+		node, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return nil, fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		doc.Nodes = append(doc.Nodes, NewNode(node))
+
+		// reverse the edges, so they point from issuer to issued
+		for destNodeHash := range amap[nodeHash] {
+			doc.Edges = append(doc.Edges, Edge{From: destNodeHash, To: nodeHash})
+		}
+	}
+
+	slices.SortFunc(doc.Edges, func(a, b Edge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+
+	return doc, nil
+}
+
+// NewNode converts a single graph node into its JSON representation.
+func NewNode(n pkigraph.Node) Node {
+	obj := n.Object()
+
+	node := Node{
+		ID:         n.Hash(),
+		Kind:       n.Kind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       n.Name(),
+		Labels:     obj.GetLabels(),
+		Synthetic:  n.Synthetic,
+		IsCA:       n.IsCA(),
+		IssuerType: n.IssuerType(),
+	}
+
+	if n.Certificate != nil {
+		node.SecretName = n.Certificate.Spec.SecretName
+		node.DNSNames = n.Certificate.Spec.DNSNames
+	}
+
+	return node
+}
@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package markdown
+
+import (
+	"go.xrstf.de/pkiplot/pkg/render"
+)
+
+func init() {
+	// use the registered Mermaid renderer, so the embedded diagram respects
+	// the --mermaid-* flags
+	diagram, _ := render.Get("mermaid")
+
+	render.Register("markdown", New(Options{Diagram: diagram}))
+}
@@ -0,0 +1,308 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/lint"
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/render/mermaid"
+	"go.xrstf.de/pkiplot/pkg/theme"
+	"go.xrstf.de/pkiplot/pkg/types"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type Options struct {
+	// Title is used as the report's top-level heading.
+	Title string
+	// DisableDiagram skips the embedded Mermaid diagram.
+	DisableDiagram bool
+	// Diagram renders the embedded diagram; defaults to a Mermaid renderer
+	// with default options.
+	Diagram render.Renderer
+	// DisabledLintRules are not checked for the findings section, see
+	// lint.RuleNames.
+	DisabledLintRules []string
+}
+
+type renderer struct {
+	opt Options
+}
+
+var (
+	_ render.ThemedRenderer  = &renderer{}
+	_ render.LintingRenderer = &renderer{}
+)
+
+func New(opt Options) *renderer {
+	if opt.Title == "" {
+		opt.Title = "PKI Report"
+	}
+
+	if opt.Diagram == nil {
+		opt.Diagram = mermaid.New(mermaid.Options{})
+	}
+
+	return &renderer{opt: opt}
+}
+
+// WithTheme returns a copy of the renderer whose diagram uses the given theme.
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	opt := r.opt
+	if diagram, ok := opt.Diagram.(render.ThemedRenderer); ok {
+		opt.Diagram = diagram.WithTheme(th)
+	}
+
+	return New(opt)
+}
+
+func (r *renderer) WithDisabledLintRules(rules []string) render.Renderer {
+	opt := r.opt
+	opt.DisabledLintRules = rules
+
+	return New(opt)
+}
+
+const clusterScoped = "Cluster-scoped"
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&r.opt.Title, "markdown-title", "", r.opt.Title, "Markdown: title of the report")
+	fs.BoolVarP(&r.opt.DisableDiagram, "markdown-disable-diagram", "", r.opt.DisableDiagram, "Markdown: do not embed a Mermaid diagram")
+}
+
+func (r *renderer) ValidateFlags() error {
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	// sort nodes alphabetically for stable output order
+	var nodes []pkigraph.Node
+	for _, nodeHash := range sets.List(sets.KeySet(amap)) {
+		node, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	buf := types.NewErrWriter(w)
+	buf.Printf("# %s\n\n", r.opt.Title)
+
+	problems, err := lint.Check(pki, r.opt.DisabledLintRules)
+	if err != nil {
+		return err
+	}
+
+	writeSummary(buf, nodes, problems)
+
+	if !r.opt.DisableDiagram {
+		var diagram bytes.Buffer
+		if err := r.opt.Diagram.Render(ctx, &diagram, pki); err != nil {
+			return fmt.Errorf("failed to render diagram: %w", err)
+		}
+
+		buf.WriteString("## Diagram\n\n```mermaid\n")
+		buf.WriteString(diagram.String())
+		buf.WriteString("```\n\n")
+	}
+
+	namespaces := map[string][]pkigraph.Node{}
+	for _, node := range nodes {
+		if node.Synthetic {
+			continue
+		}
+
+		ns := node.Object().GetNamespace()
+		if ns == "" {
+			ns = clusterScoped
+		}
+
+		namespaces[ns] = append(namespaces[ns], node)
+	}
+
+	// cluster-scoped resources come first, as everything else depends on them
+	nsNames := sets.List(sets.KeySet(namespaces))
+	if _, ok := namespaces[clusterScoped]; ok {
+		nsNames = append([]string{clusterScoped}, sets.List(sets.KeySet(namespaces).Delete(clusterScoped))...)
+	}
+
+	for _, ns := range nsNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if ns == clusterScoped {
+			buf.Printf("## %s\n\n", ns)
+		} else {
+			buf.Printf("## Namespace `%s`\n\n", ns)
+		}
+
+		writeIssuers(buf, namespaces[ns])
+		writeCertificates(buf, namespaces[ns])
+	}
+
+	writeFindings(buf, problems)
+
+	return buf.Err()
+}
+
+func writeSummary(buf *types.ErrWriter, nodes []pkigraph.Node, problems []lint.Finding) {
+	counts := map[string]int{}
+	namespaces := sets.New[string]()
+
+	for _, node := range nodes {
+		if node.Synthetic {
+			continue
+		}
+
+		counts[node.TypeName()]++
+
+		if ns := node.Object().GetNamespace(); ns != "" {
+			namespaces.Insert(ns)
+		}
+	}
+
+	buf.WriteString("## Summary\n\n")
+	buf.WriteString("| Resource | Count |\n")
+	buf.WriteString("| -------- | ----: |\n")
+	buf.Printf("| Namespaces | %d |\n", namespaces.Len())
+
+	for _, typeName := range []string{"ClusterIssuer", "Issuer", "CA Certificate", "Certificate", "Secret"} {
+		buf.Printf("| %ss | %d |\n", typeName, counts[typeName])
+	}
+
+	buf.Printf("| Findings | %d |\n\n", len(problems))
+}
+
+func writeIssuers(buf *types.ErrWriter, nodes []pkigraph.Node) {
+	var rows []string
+
+	for _, node := range nodes {
+		if node.Issuer == nil && node.ClusterIssuer == nil {
+			continue
+		}
+
+		issuerType := node.IssuerType()
+		if issuerType == "" {
+			issuerType = "unknown"
+		}
+
+		rows = append(rows, fmt.Sprintf("| `%s` | %s | %s |", node.Name(), node.Kind(), issuerType))
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+
+	buf.WriteString("### Issuers\n\n")
+	buf.WriteString("| Name | Kind | Type |\n")
+	buf.WriteString("| ---- | ---- | ---- |\n")
+	buf.WriteString(strings.Join(rows, "\n"))
+	buf.WriteString("\n\n")
+}
+
+func writeCertificates(buf *types.ErrWriter, nodes []pkigraph.Node) {
+	var rows []string
+
+	for _, node := range nodes {
+		cert := node.Certificate
+		if cert == nil {
+			continue
+		}
+
+		duration, renewBefore := "-", "-"
+		if cert.Spec.Duration != nil {
+			duration = cert.Spec.Duration.Duration.String()
+		}
+		if cert.Spec.RenewBefore != nil {
+			renewBefore = cert.Spec.RenewBefore.Duration.String()
+		}
+
+		isCA := "no"
+		if cert.Spec.IsCA {
+			isCA = "yes"
+		}
+
+		rows = append(rows, fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s | %s |",
+			node.Name(), code(cert.Spec.SecretName), issuerRef(node), isCA, codeList(cert.Spec.DNSNames), duration, renewBefore))
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+
+	buf.WriteString("### Certificates\n\n")
+	buf.WriteString("| Name | Secret | Issuer | CA | DNS Names | Duration | Renew Before |\n")
+	buf.WriteString("| ---- | ------ | ------ | -- | --------- | -------- | ------------ |\n")
+	buf.WriteString(strings.Join(rows, "\n"))
+	buf.WriteString("\n\n")
+}
+
+func writeFindings(buf *types.ErrWriter, problems []lint.Finding) {
+	buf.WriteString("## Findings\n\n")
+
+	if len(problems) == 0 {
+		buf.WriteString("No problems found.\n")
+		return
+	}
+
+	buf.WriteString("| Resource | Finding | Rule |\n")
+	buf.WriteString("| -------- | ------- | ---- |\n")
+
+	for _, problem := range problems {
+		name := problem.Node.Name()
+		if ns := problem.Node.Object().GetNamespace(); ns != "" {
+			name = ns + "/" + name
+		}
+
+		buf.Printf("| %s `%s` | %s | `%s` |\n", problem.Node.Kind(), name, problem.Message, problem.Rule)
+	}
+}
+
+func issuerRef(node pkigraph.Node) string {
+	ref := node.Certificate.Spec.IssuerRef
+
+	kind := ref.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+
+	return fmt.Sprintf("%s `%s`", kind, ref.Name)
+}
+
+func code(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return "`" + s + "`"
+}
+
+func codeList(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = code(value)
+	}
+
+	return strings.Join(quoted, ", ")
+}
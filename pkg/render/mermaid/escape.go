@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package mermaid
+
+import (
+	"strings"
+)
+
+// labelEscaper replaces characters that would end a quoted label or be
+// interpreted as HTML with Mermaid's entity codes.
+var labelEscaper = strings.NewReplacer(
+	`#`, `#35;`,
+	`"`, `#quot;`,
+	`&`, `#amp;`,
+	`<`, `#lt;`,
+	`>`, `#gt;`,
+)
+
+func escapeLabel(text string) string {
+	return labelEscaper.Replace(text)
+}
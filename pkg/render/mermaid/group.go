@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package mermaid
+
+import (
+	"github.com/dominikbraun/graph"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render/ident"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	GroupByNone      = "none"
+	GroupByNamespace = "namespace"
+	GroupByIssuer    = "issuer"
+)
+
+var groupByModes = []string{GroupByNone, GroupByNamespace, GroupByIssuer}
+
+var directions = []string{"TB", "LR", "BT", "RL"}
+
+// group is rendered as a subgraph.
+type group struct {
+	id    string
+	title string
+}
+
+// clusterScopedGroup contains the ClusterIssuers when grouping by namespace.
+var clusterScopedGroup = group{id: "cluster_scoped", title: "cluster-scoped"}
+
+// nodeGroup returns the group a node belongs to, or nil if it should not be
+// in any subgraph.
+func nodeGroup(pki pkigraph.Graph, amap map[string]map[string]graph.Edge[string], ids ident.Identifiers, node pkigraph.Node, groupBy string) *group {
+	switch groupBy {
+	case GroupByNamespace:
+		ns := node.Object().GetNamespace()
+		if ns == "" {
+			return &clusterScopedGroup
+		}
+
+		return &group{id: "namespace_" + ident.Sanitize(ns), title: ns}
+
+	case GroupByIssuer:
+		return issuerGroup(pki, amap, ids, node)
+
+	default:
+		return nil
+	}
+}
+
+// issuerGroup groups every issuer with the Certificates it issues and
+// their Secrets.
+func issuerGroup(pki pkigraph.Graph, amap map[string]map[string]graph.Edge[string], ids ident.Identifiers, node pkigraph.Node) *group {
+	if node.Issuer != nil || node.ClusterIssuer != nil {
+		return &group{id: "group_" + ids.Get(node), title: node.Name()}
+	}
+
+	// sorted, in case multiple Certificates create the same Secret
+	for _, dependencyHash := range sets.List(sets.KeySet(amap[node.Hash()])) {
+		dependency, err := pki.Raw().Vertex(dependencyHash)
+		if err != nil {
+			continue
+		}
+
+		// follow Certificates to their issuer and Secrets to their Certificate
+		switch pkigraph.EdgeTypeOf(node, dependency) {
+		case pkigraph.EdgeIssues, pkigraph.EdgeCreates:
+			return issuerGroup(pki, amap, ids, dependency)
+		}
+	}
+
+	return nil
+}
@@ -8,5 +8,5 @@ import (
 )
 
 func init() {
-	render.Register("mermaid", New())
+	render.Register("mermaid", New(Options{}))
 }
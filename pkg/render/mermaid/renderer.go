@@ -4,82 +4,202 @@
 package mermaid
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/pflag"
 
 	"go.xrstf.de/pkiplot/pkg/pkigraph"
 	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/render/ident"
+	"go.xrstf.de/pkiplot/pkg/theme"
 	"go.xrstf.de/pkiplot/pkg/types"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
-type renderer struct{}
+type Options struct {
+	// ShowType includes a node's type in its label.
+	ShowType bool
+	// DisableClassDefs skips the classDef statements at the end of the diagram.
+	DisableClassDefs bool
+	// ClassStyles overrides the style for the given node classes.
+	ClassStyles map[string]string
+	// Direction is the flowchart direction, one of TB, LR, BT or RL.
+	Direction string
+	// GroupBy puts nodes into subgraphs, one of none, namespace or issuer.
+	GroupBy string
+	// Theme styles the output; defaults to theme.Default.
+	Theme *theme.Theme
+}
+
+type renderer struct {
+	opt             Options
+	classStyleFlags []string
+}
+
+var _ render.ThemedRenderer = &renderer{}
+
+func New(opt Options) *renderer {
+	if opt.Direction == "" {
+		opt.Direction = "TB"
+	}
+
+	if opt.GroupBy == "" {
+		opt.GroupBy = GroupByNone
+	}
 
-var _ render.Renderer = &renderer{}
+	if opt.Theme == nil {
+		opt.Theme = theme.Default
+	}
 
-func New() *renderer {
-	return &renderer{}
+	return &renderer{opt: opt}
 }
 
-var (
-	showLabels       bool
-	disableClassDefs bool
-)
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	opt := r.opt
+	opt.Theme = th
+
+	return New(opt)
+}
 
 func (r *renderer) AddFlags(fs *pflag.FlagSet) {
-	fs.BoolVarP(&showLabels, "mermaid-show-type", "", showLabels, "Mermaid: include a node's type in the node label")
-	fs.BoolVarP(&disableClassDefs, "mermaid-disable-classdefs", "", disableClassDefs, "Mermaid: do not output classDef statements")
+	fs.BoolVarP(&r.opt.ShowType, "mermaid-show-type", "", r.opt.ShowType, "Mermaid: include a node's type in the node label")
+	fs.BoolVarP(&r.opt.DisableClassDefs, "mermaid-disable-classdefs", "", r.opt.DisableClassDefs, "Mermaid: do not output classDef and other styling statements")
+	fs.StringVarP(&r.opt.Direction, "mermaid-direction", "", r.opt.Direction, fmt.Sprintf("Mermaid: direction of the diagram, one of %v", directions))
+	fs.StringVarP(&r.opt.GroupBy, "mermaid-group-by", "", r.opt.GroupBy, fmt.Sprintf("Mermaid: group nodes into subgraphs, one of %v", groupByModes))
+	fs.StringArrayVarP(&r.classStyleFlags, "mermaid-class-style", "", r.classStyleFlags, "Mermaid: override the style of a node class, e.g. \"ca=fill:#F77,stroke:#333\" (can be given multiple times)")
 }
 
 func (r *renderer) ValidateFlags() error {
+	if !slices.Contains(directions, r.opt.Direction) {
+		return fmt.Errorf("invalid direction %q, must be one of %v", r.opt.Direction, directions)
+	}
+
+	if !slices.Contains(groupByModes, r.opt.GroupBy) {
+		return fmt.Errorf("invalid grouping %q, must be one of %v", r.opt.GroupBy, groupByModes)
+	}
+
+	if r.opt.ClassStyles == nil {
+		r.opt.ClassStyles = map[string]string{}
+	}
+
+	for _, flag := range r.classStyleFlags {
+		class, style, found := strings.Cut(flag, "=")
+		if !found || class == "" || style == "" {
+			return fmt.Errorf("invalid class style %q, must be in the form of class=style", flag)
+		}
+
+		r.opt.ClassStyles[class] = style
+	}
+
 	return nil
 }
 
-func (r *renderer) RenderGraph(pki pkigraph.Graph) (string, error) {
-	var buf types.StringBuilder
-	buf.WriteString("graph TB\n")
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	buf := types.NewErrWriter(w)
+	buf.Printf("graph %s\n", r.opt.Direction)
 
 	amap, err := pki.Raw().AdjacencyMap()
 	if err != nil {
-		return "", fmt.Errorf("invalid graph: %w", err)
+		return fmt.Errorf("invalid graph: %w", err)
 	}
 
 	// sort nodes alphabetically for stable output order
 	nodeNames := sets.List(sets.KeySet(amap))
 
-	// first print all the nodes
+	ids, err := ident.New(pki, nodeNames)
+	if err != nil {
+		return err
+	}
+
+	th := r.opt.Theme
+
+	// collect node statements per group; ungrouped nodes use the empty key
+	groups := map[string]group{}
+	groupNodes := map[string][]string{}
+
+	// node IDs for each additional class (issuer types and states)
+	classMembers := map[string][]string{}
+
 	for _, nodeHash := range nodeNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		srcNode, err := pki.Raw().Vertex(nodeHash)
 		if err != nil {
-			return "", fmt.Errorf("inconsistent graph: %w", err)
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		var lines []string
+		for _, line := range pki.Label(srcNode) {
+			lines = append(lines, escapeLabel(line))
+		}
+
+		name := strings.Join(lines, "<br>")
+		if r.opt.ShowType {
+			name = fmt.Sprintf("<code>%s</code><br>%s", name, escapeLabel(srcNode.TypeName()))
 		}
 
-		srcNodeID := nodeID(srcNode)
+		groupID := ""
+		if g := nodeGroup(pki, amap, ids, srcNode, r.opt.GroupBy); g != nil {
+			groupID = g.id
+			groups[groupID] = *g
+		}
+
+		// the first class is assigned inline, all others (issuer types and
+		// states) are assigned using class statements
+		classes := theme.NodeClasses(srcNode)
 
-		name := objectName(srcNode.Object())
-		if showLabels {
-			name = fmt.Sprintf("<code>%s</code><br>%s", name, nodeType(srcNode))
+		shape := nodeShape(th.NodeStyle(srcNode))
+		groupNodes[groupID] = append(groupNodes[groupID], fmt.Sprintf(`%s%s"%s"%s:::%s`, ids.Get(srcNode), shape[0], name, shape[1], classes[0]))
+
+		for _, class := range classes[1:] {
+			classMembers[class] = append(classMembers[class], ids.Get(srcNode))
 		}
-		buf.Printf("\t%s([%q]):::%s\n", srcNodeID, name, nodeClass(srcNode))
+	}
+
+	// first print all the nodes
+	for _, statement := range groupNodes[""] {
+		buf.Printf("\t%s\n", statement)
+	}
+
+	for _, groupID := range sets.List(sets.KeySet(groups)) {
+		g := groups[groupID]
+
+		buf.Printf("\n\tsubgraph %s[\"%s\"]\n", g.id, escapeLabel(g.title))
+		for _, statement := range groupNodes[groupID] {
+			buf.Printf("\t\t%s\n", statement)
+		}
+		buf.WriteString("\tend\n")
 	}
 
 	buf.Printf("\n")
 
-	// then print all the edges
-	for nodeHash, edgeMap := range amap {
+	// then print all the edges, sorted as well; edges are styled by their
+	// index, so remember them per type
+	edgeIndices := map[pkigraph.EdgeType][]string{}
+	edgeIndex := 0
+
+	for _, nodeHash := range nodeNames {
 		srcNode, err := pki.Raw().Vertex(nodeHash)
 		if err != nil {
-			return "", fmt.Errorf("inconsistent graph: %w", err)
+			return fmt.Errorf("inconsistent graph: %w", err)
 		}
 
-		srcNodeID := nodeID(srcNode)
+		srcNodeID := ids.Get(srcNode)
+
+		for _, destNodeHash := range sets.List(sets.KeySet(amap[nodeHash])) {
+			edges := amap[nodeHash][destNodeHash]
 
-		for destNodeHash, edges := range edgeMap {
 			destNode, err := pki.Raw().Vertex(destNodeHash)
 			if err != nil {
-				return "", fmt.Errorf("inconsistent graph: %w", err)
+				return fmt.Errorf("inconsistent graph: %w", err)
 			}
 
 			if false {
@@ -87,18 +207,30 @@ func (r *renderer) RenderGraph(pki pkigraph.Graph) (string, error) {
 			}
 
 			// To have the chart be readable from top to bottom, we reverse the edge direction here.
-			buf.Printf("\t%s --> %s\n", nodeID(destNode), srcNodeID)
+			buf.Printf("\t%s --> %s\n", ids.Get(destNode), srcNodeID)
+
+			edgeType := pkigraph.EdgeTypeOf(srcNode, destNode)
+			edgeIndices[edgeType] = append(edgeIndices[edgeType], strconv.Itoa(edgeIndex))
+			edgeIndex++
 		}
 	}
 
-	if !disableClassDefs {
+	if !r.opt.DisableClassDefs {
 		buf.Printf("\n")
-		buf.WriteString("\tclassDef clusterissuer color:#7F7\n")
-		buf.WriteString("\tclassDef issuer color:#77F\n")
-		buf.WriteString("\tclassDef ca color:#F77\n")
-		buf.WriteString("\tclassDef certificate color:orange\n")
-		buf.WriteString("\tclassDef secret color:red")
+
+		for _, edgeType := range sets.List(sets.KeySet(edgeIndices)) {
+			if style := linkStyle(th.EdgeStyle(edgeType)); style != "" {
+				buf.Printf("\tlinkStyle %s %s\n", strings.Join(edgeIndices[edgeType], ","), style)
+			}
+		}
+
+		for _, class := range sets.List(sets.KeySet(classMembers)) {
+			buf.Printf("\tclass %s %s\n", strings.Join(classMembers[class], ","), class)
+		}
+
+		buf.WriteString(strings.Join(r.classDefs(th, sets.KeySet(classMembers)), "\n"))
+		buf.WriteString("\n")
 	}
 
-	return buf.String(), nil
+	return buf.Err()
 }
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package mermaid
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.xrstf.de/pkiplot/pkg/loader"
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/")
+
+// TestRenderGolden renders every testdata/*.yaml and compares the result with
+// the corresponding .mmd file. Run with -update to regenerate them.
+func TestRenderGolden(t *testing.T) {
+	sources, err := filepath.Glob("testdata/*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to find testdata: %v", err)
+	}
+
+	for _, source := range sources {
+		name := strings.TrimSuffix(filepath.Base(source), ".yaml")
+
+		t.Run(name, func(t *testing.T) {
+			pki, _, err := loader.LoadPKI(context.Background(), []string{source}, nil)
+			if err != nil {
+				t.Fatalf("Failed to load PKI: %v", err)
+			}
+
+			graph := pkigraph.NewFromPKI(pki, pkigraph.Options{
+				ClusterResourceNamespace: "cert-manager",
+				ShowSynthetics:           true,
+			})
+
+			rendered, err := render.ToString(context.Background(), New(Options{}), graph)
+			if err != nil {
+				t.Fatalf("Failed to render: %v", err)
+			}
+
+			golden := strings.TrimSuffix(source, ".yaml") + ".mmd"
+
+			if *update {
+				if err := os.WriteFile(golden, []byte(rendered), 0644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read golden file: %v", err)
+			}
+
+			if rendered != string(expected) {
+				t.Errorf("Output does not match %s (run with -update to regenerate it):\n%s", golden, rendered)
+			}
+		})
+	}
+}
+
+// TestIdentifiersStable ensures that adding objects does not change the
+// identifiers of existing nodes, even if their names collide.
+func TestIdentifiersStable(t *testing.T) {
+	original, err := os.ReadFile("testdata/collisions.mmd")
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+
+	added, err := os.ReadFile("testdata/collisions-added.mmd")
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+
+	addedLines := sets.New(strings.Split(string(added), "\n")...)
+
+	for _, line := range strings.Split(string(original), "\n") {
+		// node definitions and edges
+		if !strings.Contains(line, ":::") && !strings.Contains(line, "-->") {
+			continue
+		}
+
+		if !addedLines.Has(line) {
+			t.Errorf("Line %q is missing after adding unrelated objects.", strings.TrimSpace(line))
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// shapes are the brackets around node labels for each theme shape.
+var shapes = map[string][2]string{
+	theme.ShapeStadium:   {"([", "])"},
+	theme.ShapeRectangle: {"[", "]"},
+	theme.ShapeRounded:   {"(", ")"},
+	theme.ShapeHexagon:   {"{{", "}}"},
+	theme.ShapeCircle:    {"((", "))"},
+}
+
+func nodeShape(style theme.Style) [2]string {
+	if shape, ok := shapes[style.Shape]; ok {
+		return shape
+	}
+
+	return shapes[theme.ShapeStadium]
+}
+
+// classStyle converts a theme style into Mermaid's CSS-like syntax.
+func classStyle(style theme.Style) string {
+	var properties []string
+
+	if style.Color != "" {
+		properties = append(properties, "color:"+style.Color)
+	}
+
+	if style.Fill != "" {
+		properties = append(properties, "fill:"+style.Fill)
+		if style.Color != "" {
+			properties = append(properties, "stroke:"+style.Color)
+		}
+	}
+
+	if style.Dashed {
+		properties = append(properties, "stroke-dasharray:5 5")
+	}
+
+	return strings.Join(properties, ",")
+}
+
+// linkStyle converts a theme style into Mermaid's syntax for edges.
+func linkStyle(style theme.Style) string {
+	var properties []string
+
+	if style.Color != "" {
+		properties = append(properties, "stroke:"+style.Color)
+	}
+
+	if style.Dashed {
+		properties = append(properties, "stroke-dasharray:5 5")
+	}
+
+	return strings.Join(properties, ",")
+}
+
+// classDefs returns the classDef statements for all node classes and the
+// used issuer types and states, in this order so that more specific classes
+// win. Custom styles given by the user replace the theme's styles.
+func (r *renderer) classDefs(th *theme.Theme, used sets.Set[string]) []string {
+	var (
+		defs    []string
+		defined = sets.New[string]()
+	)
+
+	define := func(class string, style theme.Style) {
+		css := classStyle(style)
+		if custom, ok := r.opt.ClassStyles[class]; ok {
+			css = custom
+		}
+
+		defined.Insert(class)
+		if css != "" {
+			defs = append(defs, fmt.Sprintf("\tclassDef %s %s", class, css))
+		}
+	}
+
+	for _, class := range pkigraph.Classes {
+		define(class, th.ClassStyle(class))
+	}
+
+	for _, issuerType := range sets.List(sets.KeySet(th.IssuerTypes)) {
+		if class := theme.IssuerTypeClass(issuerType); used.Has(class) {
+			define(class, th.IssuerTypes[issuerType])
+		}
+	}
+
+	for _, state := range theme.States {
+		if style, ok := th.StateStyle(state); ok && used.Has(state) {
+			define(state, style)
+		}
+	}
+
+	for _, class := range sets.List(sets.KeySet(r.opt.ClassStyles)) {
+		if !defined.Has(class) {
+			defs = append(defs, fmt.Sprintf("\tclassDef %s %s", class, r.opt.ClassStyles[class]))
+		}
+	}
+
+	return defs
+}
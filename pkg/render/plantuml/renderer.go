@@ -0,0 +1,282 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package plantuml
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/render/ident"
+	"go.xrstf.de/pkiplot/pkg/theme"
+	"go.xrstf.de/pkiplot/pkg/types"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	ComponentDiagram = "component"
+	ObjectDiagram    = "object"
+)
+
+type Options struct {
+	// Diagram is either "component" or "object"; object diagrams list
+	// additional details for each node.
+	Diagram string
+	// DisableLegend skips the legend explaining the node colors.
+	DisableLegend bool
+	// Theme styles the output; defaults to theme.Default.
+	Theme *theme.Theme
+}
+
+type renderer struct {
+	opt Options
+}
+
+var _ render.ThemedRenderer = &renderer{}
+
+func New(opt Options) *renderer {
+	if opt.Diagram == "" {
+		opt.Diagram = ComponentDiagram
+	}
+
+	if opt.Theme == nil {
+		opt.Theme = theme.Default
+	}
+
+	return &renderer{opt: opt}
+}
+
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	opt := r.opt
+	opt.Theme = th
+
+	return New(opt)
+}
+
+// classes are used as stereotypes, in the order they appear in the legend.
+var classes = []struct {
+	name  string
+	title string
+}{
+	{"clusterissuer", "ClusterIssuer"},
+	{"issuer", "Issuer"},
+	{"ca", "CA Certificate"},
+	{"certificate", "Certificate"},
+	{"secret", "Secret"},
+}
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&r.opt.Diagram, "plantuml-diagram", "", r.opt.Diagram, "PlantUML: diagram type, one of [component object]")
+	fs.BoolVarP(&r.opt.DisableLegend, "plantuml-disable-legend", "", r.opt.DisableLegend, "PlantUML: do not output a legend")
+}
+
+func (r *renderer) ValidateFlags() error {
+	if r.opt.Diagram != ComponentDiagram && r.opt.Diagram != ObjectDiagram {
+		return fmt.Errorf("invalid diagram type %q, must be one of [%s %s]", r.opt.Diagram, ComponentDiagram, ObjectDiagram)
+	}
+
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	buf := types.NewErrWriter(w)
+	buf.WriteString("@startuml\n")
+	buf.WriteString("top to bottom direction\n\n")
+
+	if err := r.writeSkinParams(buf); err != nil {
+		return err
+	}
+
+	// group nodes by namespace, cluster-scoped nodes are not in any package
+	namespaces := map[string][]pkigraph.Node{}
+
+	// sort nodes alphabetically for stable output order
+	nodeNames := sets.List(sets.KeySet(amap))
+
+	ids, err := ident.New(pki, nodeNames)
+	if err != nil {
+		return err
+	}
+
+	for _, nodeHash := range nodeNames {
+		node, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		ns := node.Object().GetNamespace()
+		namespaces[ns] = append(namespaces[ns], node)
+	}
+
+	for _, node := range namespaces[""] {
+		if err := r.writeNode(buf, ids, node, pki.Label(node), ""); err != nil {
+			return err
+		}
+	}
+
+	for _, ns := range sets.List(sets.KeySet(namespaces)) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if ns == "" {
+			continue
+		}
+
+		buf.Printf("\npackage %q {\n", ns)
+		for _, node := range namespaces[ns] {
+			if err := r.writeNode(buf, ids, node, pki.Label(node), "\t"); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}\n")
+	}
+
+	buf.WriteString("\n")
+
+	for _, nodeHash := range nodeNames {
+		srcNode, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		for _, destNodeHash := range sets.List(sets.KeySet(amap[nodeHash])) {
+			destNode, err := pki.Raw().Vertex(destNodeHash)
+			if err != nil {
+				return fmt.Errorf("inconsistent graph: %w", err)
+			}
+
+			// To have the chart be readable from top to bottom, we reverse the edge direction here.
+			buf.Printf("%s --> %s\n", ids.Get(destNode), ids.Get(srcNode))
+		}
+	}
+
+	if !r.opt.DisableLegend {
+		buf.WriteString("\nlegend right\n")
+		for _, class := range classes {
+			col, _, err := r.opt.Theme.ClassStyle(class.name).Colors()
+			if err != nil {
+				return err
+			}
+
+			buf.Printf("\t|<%s>     | %s |\n", theme.Hex(col), class.title)
+		}
+		if r.opt.Theme.States[theme.StateSynthetic].Dashed {
+			buf.WriteString("\t| dashed | not found in the manifests |\n")
+		}
+		buf.WriteString("endlegend\n")
+	}
+
+	buf.WriteString("@enduml\n")
+
+	return buf.Err()
+}
+
+func (r *renderer) writeSkinParams(buf *types.ErrWriter) error {
+	buf.Printf("skinparam %s {\n", r.opt.Diagram)
+
+	for _, class := range classes {
+		style := r.opt.Theme.ClassStyle(class.name)
+
+		col, fill, err := style.Colors()
+		if err != nil {
+			return err
+		}
+
+		buf.Printf("\tBackgroundColor<<%s>> %s\n", class.name, theme.Hex(fill))
+		buf.Printf("\tBorderColor<<%s>> %s\n", class.name, theme.Hex(col))
+		if style.Dashed {
+			buf.Printf("\tBorderStyle<<%s>> dashed\n", class.name)
+		}
+	}
+
+	buf.WriteString("}\n\n")
+
+	return nil
+}
+
+func (r *renderer) writeNode(buf *types.ErrWriter, ids ident.Identifiers, node pkigraph.Node, label []string, indent string) error {
+	// PlantUML turns \n into line breaks
+	name := quote(strings.Join(label, `\n`))
+
+	style, err := inlineStyle(r.opt.Theme, node)
+	if err != nil {
+		return err
+	}
+
+	if r.opt.Diagram == ComponentDiagram {
+		buf.Printf("%scomponent %s as %s <<%s>>%s\n", indent, name, ids.Get(node), node.Class(), style)
+		return nil
+	}
+
+	buf.Printf("%sobject %s as %s <<%s>>%s {\n", indent, name, ids.Get(node), node.Class(), style)
+	for _, field := range objectFields(node) {
+		buf.Printf("%s\t%s = %s\n", indent, field[0], field[1])
+	}
+	buf.Printf("%s}\n", indent)
+
+	return nil
+}
+
+// inlineStyle returns the node's style if it differs from the skinparams of
+// its class, e.g. because of its issuer type or state.
+func inlineStyle(th *theme.Theme, node pkigraph.Node) (string, error) {
+	style := th.NodeStyle(node)
+	if style == th.ClassStyle(node.Class()) {
+		return "", nil
+	}
+
+	col, fill, err := style.Colors()
+	if err != nil {
+		return "", err
+	}
+
+	inline := fmt.Sprintf(" %s;line:%s", theme.Hex(fill), theme.Hex(col))
+	if style.Dashed {
+		inline += ";line.dashed"
+	}
+
+	return inline, nil
+}
+
+func objectFields(node pkigraph.Node) [][2]string {
+	fields := [][2]string{{"kind", node.Kind()}}
+
+	if ns := node.Object().GetNamespace(); ns != "" {
+		fields = append(fields, [2]string{"namespace", ns})
+	}
+
+	if issuerType := node.IssuerType(); issuerType != "" {
+		fields = append(fields, [2]string{"type", issuerType})
+	}
+
+	if cert := node.Certificate; cert != nil {
+		fields = append(fields, [2]string{"isCA", fmt.Sprintf("%v", cert.Spec.IsCA)})
+
+		if len(cert.Spec.DNSNames) > 0 {
+			fields = append(fields, [2]string{"dnsNames", strings.Join(cert.Spec.DNSNames, ", ")})
+		}
+	}
+
+	if node.Synthetic {
+		fields = append(fields, [2]string{"found", "false"})
+	}
+
+	return fields
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `'`) + `"`
+}
@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package png
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"go.xrstf.de/pkiplot/pkg/diagram"
+	"go.xrstf.de/pkiplot/pkg/layout"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"golang.org/x/image/vector"
+)
+
+const (
+	nodeStrokeWidth = 2
+	edgeStrokeWidth = 1.5
+	arrowLength     = 9
+	arrowWidth      = 8
+	dashLength      = 5
+	dashGap         = 3
+	curveSegments   = 16
+	arcSegments     = 12
+)
+
+// canvas draws in diagram units, which are scaled to pixels.
+type canvas struct {
+	img   *image.RGBA
+	scale float64
+	// z is reused for all shapes to avoid allocating a rasterizer for each.
+	z vector.Rasterizer
+}
+
+// fillPolygons fills all polygons in one pass, limited to their bounding box.
+// All polygons must have the same winding direction.
+func (c *canvas) fillPolygons(polygons [][]layout.Point, col color.Color) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+
+	for _, polygon := range polygons {
+		for _, p := range polygon {
+			minX, minY = min(minX, p.X*c.scale), min(minY, p.Y*c.scale)
+			maxX, maxY = max(maxX, p.X*c.scale), max(maxY, p.Y*c.scale)
+		}
+	}
+
+	bounds := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))).Intersect(c.img.Bounds())
+	if bounds.Empty() {
+		return
+	}
+
+	// the rasterizer only covers the bounding box, so all points are moved
+	// relative to its top-left corner
+	offsetX, offsetY := float64(bounds.Min.X), float64(bounds.Min.Y)
+
+	c.z.Reset(bounds.Dx(), bounds.Dy())
+	c.z.DrawOp = draw.Over
+
+	for _, polygon := range polygons {
+		if len(polygon) < 3 {
+			continue
+		}
+
+		c.z.MoveTo(float32(polygon[0].X*c.scale-offsetX), float32(polygon[0].Y*c.scale-offsetY))
+		for _, p := range polygon[1:] {
+			c.z.LineTo(float32(p.X*c.scale-offsetX), float32(p.Y*c.scale-offsetY))
+		}
+		c.z.ClosePath()
+	}
+
+	c.z.Draw(c.img, bounds, image.NewUniform(col), image.Point{})
+}
+
+// lineOutline returns the outline of a single straight line with square ends.
+func lineOutline(a, b layout.Point, width float64) []layout.Point {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return nil
+	}
+
+	// normal vector with half the line width
+	nx, ny := -dy/length*width/2, dx/length*width/2
+
+	return []layout.Point{
+		{X: a.X + nx, Y: a.Y + ny},
+		{X: b.X + nx, Y: b.Y + ny},
+		{X: b.X - nx, Y: b.Y - ny},
+		{X: a.X - nx, Y: a.Y - ny},
+	}
+}
+
+// polylineOutlines returns the outlines of connected lines, optionally dashed.
+func polylineOutlines(points []layout.Point, width float64, dashed bool) [][]layout.Point {
+	var outlines [][]layout.Point
+
+	// position within the current dash pattern
+	offset := 0.0
+
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+
+		if !dashed {
+			outlines = append(outlines, lineOutline(a, b, width))
+			continue
+		}
+
+		length := math.Hypot(b.X-a.X, b.Y-a.Y)
+		for pos := 0.0; pos < length; {
+			period := math.Mod(offset+pos, dashLength+dashGap)
+			step := min(length-pos, dashLength+dashGap-period)
+
+			if period < dashLength {
+				step = min(step, dashLength-period)
+				outlines = append(outlines, lineOutline(interpolate(a, b, pos/length), interpolate(a, b, (pos+step)/length), width))
+			}
+
+			pos += step
+		}
+
+		offset += length
+	}
+
+	return outlines
+}
+
+// drawShape fills and strokes a node outline.
+func (c *canvas) drawShape(outline []layout.Point, fill, stroke color.Color, dashed bool) {
+	c.fillPolygons([][]layout.Point{outline}, fill)
+	c.fillPolygons(polylineOutlines(append(outline, outline[0]), nodeStrokeWidth, dashed), stroke)
+}
+
+// shapeOutline returns the outline of a node with the given theme shape,
+// clockwise starting at the top.
+func shapeOutline(shape string, x, y, w, h float64) []layout.Point {
+	switch shape {
+	case theme.ShapeRectangle:
+		return []layout.Point{{X: x, Y: y}, {X: x + w, Y: y}, {X: x + w, Y: y + h}, {X: x, Y: y + h}}
+
+	case theme.ShapeRounded:
+		return roundedRect(x, y, w, h, min(diagram.RoundedRadius, w/2, h/2))
+
+	case theme.ShapeHexagon:
+		inset := min(diagram.HexagonInset, w/2)
+
+		return []layout.Point{
+			{X: x + inset, Y: y},
+			{X: x + w - inset, Y: y},
+			{X: x + w, Y: y + h/2},
+			{X: x + w - inset, Y: y + h},
+			{X: x + inset, Y: y + h},
+			{X: x, Y: y + h/2},
+		}
+
+	case theme.ShapeCircle:
+		points := make([]layout.Point, 0, 4*arcSegments)
+		for i := range 4 * arcSegments {
+			angle := -math.Pi/2 + 2*math.Pi*float64(i)/(4*arcSegments)
+			points = append(points, layout.Point{X: x + w/2 + w/2*math.Cos(angle), Y: y + h/2 + h/2*math.Sin(angle)})
+		}
+
+		return points
+
+	default:
+		return roundedRect(x, y, w, h, min(w, h)/2)
+	}
+}
+
+// roundedRect returns the outline of a rectangle whose corners are rounded
+// with radius r; with r being half the height, this is a stadium.
+func roundedRect(x, y, w, h, r float64) []layout.Point {
+	var points []layout.Point
+
+	// quarter circles for the top right, bottom right, bottom left and top
+	// left corners
+	corners := []struct{ cx, cy float64 }{
+		{x + w - r, y + r},
+		{x + w - r, y + h - r},
+		{x + r, y + h - r},
+		{x + r, y + r},
+	}
+
+	for i, corner := range corners {
+		from := -math.Pi/2 + math.Pi/2*float64(i)
+		for j := 0; j <= arcSegments/2; j++ {
+			angle := from + math.Pi/2*float64(j)/(arcSegments/2)
+			points = append(points, layout.Point{X: corner.cx + r*math.Cos(angle), Y: corner.cy + r*math.Sin(angle)})
+		}
+	}
+
+	return points
+}
+
+// drawEdge draws the same curves as the SVG renderer, ending in an arrow.
+func (c *canvas) drawEdge(points []layout.Point, col color.Color, dashed bool) {
+	if len(points) < 2 {
+		return
+	}
+
+	line := []layout.Point{points[0]}
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1], points[i]
+		midY := (from.Y + to.Y) / 2
+
+		line = append(line, flattenCubic(from, layout.Point{X: from.X, Y: midY}, layout.Point{X: to.X, Y: midY}, to)...)
+	}
+
+	// shorten the line, so it does not poke through the arrow's tip
+	tip := line[len(line)-1]
+	prev := line[len(line)-2]
+	length := math.Hypot(tip.X-prev.X, tip.Y-prev.Y)
+	if length == 0 {
+		return
+	}
+
+	dx, dy := (tip.X-prev.X)/length, (tip.Y-prev.Y)/length
+	base := layout.Point{X: tip.X - dx*arrowLength, Y: tip.Y - dy*arrowLength}
+	line[len(line)-1] = base
+
+	// the line and its arrow are drawn at once, so they do not overlap
+	// visibly where they meet
+	outlines := polylineOutlines(line, edgeStrokeWidth, dashed)
+	outlines = append(outlines, []layout.Point{
+		tip,
+		{X: base.X + dy*arrowWidth/2, Y: base.Y - dx*arrowWidth/2},
+		{X: base.X - dy*arrowWidth/2, Y: base.Y + dx*arrowWidth/2},
+	})
+
+	c.fillPolygons(outlines, col)
+}
+
+// flattenCubic approximates a cubic bezier curve, excluding its start point.
+func flattenCubic(p0, p1, p2, p3 layout.Point) []layout.Point {
+	points := make([]layout.Point, 0, curveSegments)
+
+	for i := 1; i <= curveSegments; i++ {
+		t := float64(i) / curveSegments
+		u := 1 - t
+
+		points = append(points, layout.Point{
+			X: u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*p3.X,
+			Y: u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*p3.Y,
+		})
+	}
+
+	return points
+}
+
+func interpolate(a, b layout.Point, t float64) layout.Point {
+	return layout.Point{X: a.X + (b.X-a.X)*t, Y: a.Y + (b.Y-a.Y)*t}
+}
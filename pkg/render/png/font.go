@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package png
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// The Go fonts are embedded, so rendering does not depend on any fonts
+// being installed on the system.
+var (
+	parseFonts sync.Once
+	fontErr    error
+	regular    *opentype.Font
+	bold       *opentype.Font
+)
+
+func loadFonts() error {
+	parseFonts.Do(func() {
+		if regular, fontErr = opentype.Parse(goregular.TTF); fontErr != nil {
+			return
+		}
+
+		bold, fontErr = opentype.Parse(gobold.TTF)
+	})
+
+	if fontErr != nil {
+		return fmt.Errorf("failed to parse font: %w", fontErr)
+	}
+
+	return nil
+}
+
+// newFace returns a face where the size is given in pixels.
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingNone,
+	})
+}
+
+func toFloat(x fixed.Int26_6) float64 {
+	return float64(x) / 64
+}
+
+// measureBold measures text as it is drawn in the first label line, which
+// is always the widest variant.
+func measureBold(text string, fontSize float64) float64 {
+	face, err := newFace(bold, fontSize)
+	if err != nil {
+		return 0
+	}
+	defer face.Close()
+
+	return toFloat(font.MeasureString(face, text))
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package png
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/diagram"
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/theme"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// baseDPI is the resolution at which one diagram unit equals one pixel.
+const baseDPI = 96
+
+// maxPixels protects against accidentally allocating huge images.
+const maxPixels = 200_000_000
+
+type Options struct {
+	// ShowType includes a node's type in its label.
+	ShowType bool
+	// DPI scales the image, with 96 DPI being the natural size.
+	DPI float64
+	// Width is the image width in pixels; if set, it takes precedence over DPI.
+	Width int
+	// Theme styles the output; defaults to theme.Default.
+	Theme *theme.Theme
+}
+
+type renderer struct {
+	opt Options
+}
+
+var _ render.ThemedRenderer = &renderer{}
+
+func New(opt Options) *renderer {
+	if opt.DPI == 0 {
+		opt.DPI = baseDPI
+	}
+
+	if opt.Theme == nil {
+		opt.Theme = theme.Default
+	}
+
+	return &renderer{opt: opt}
+}
+
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	opt := r.opt
+	opt.Theme = th
+
+	return New(opt)
+}
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVarP(&r.opt.ShowType, "png-show-type", "", r.opt.ShowType, "PNG: include a node's type in the node label")
+	fs.Float64VarP(&r.opt.DPI, "png-dpi", "", r.opt.DPI, "PNG: resolution of the image, 96 DPI is the natural size")
+	fs.IntVarP(&r.opt.Width, "png-width", "", r.opt.Width, "PNG: width of the image in pixels, overrides --png-dpi (0 to disable)")
+}
+
+func (r *renderer) ValidateFlags() error {
+	if r.opt.DPI <= 0 {
+		return errors.New("DPI must be positive")
+	}
+
+	if r.opt.Width < 0 {
+		return errors.New("width must not be negative")
+	}
+
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	if err := loadFonts(); err != nil {
+		return err
+	}
+
+	opt := diagram.NewDefaultOptions()
+	opt.ShowType = r.opt.ShowType
+	opt.Measure = measureBold
+	opt.Theme = r.opt.Theme
+
+	scene, err := diagram.Build(pki, opt)
+	if err != nil {
+		return err
+	}
+
+	scale := r.opt.DPI / baseDPI
+	if r.opt.Width > 0 {
+		scale = float64(r.opt.Width) / scene.Width
+	}
+
+	width := int(scene.Width*scale + 0.5)
+	height := int(scene.Height*scale + 0.5)
+	if width <= 0 || height <= 0 || width*height > maxPixels {
+		return fmt.Errorf("invalid image size %dx%d", width, height)
+	}
+
+	c := &canvas{
+		img:   image.NewRGBA(image.Rect(0, 0, width, height)),
+		scale: scale,
+	}
+	draw.Draw(c.img, c.img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for _, edge := range scene.Edges {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		col, err := theme.ParseColor(edge.Color)
+		if err != nil {
+			return err
+		}
+
+		c.drawEdge(edge.Points, col, edge.Dashed)
+	}
+
+	for _, node := range scene.Nodes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := r.drawNode(c, scene, node); err != nil {
+			return err
+		}
+	}
+
+	return png.Encode(w, c.img)
+}
+
+func (r *renderer) drawNode(c *canvas, scene *diagram.Scene, node diagram.Node) error {
+	col, err := theme.ParseColor(node.Color)
+	if err != nil {
+		return err
+	}
+
+	fill, err := theme.ParseColor(node.Fill)
+	if err != nil {
+		return err
+	}
+
+	textColor, err := theme.ParseColor(diagram.TextColor)
+	if err != nil {
+		return err
+	}
+
+	c.drawShape(shapeOutline(node.Shape, node.X, node.Y, node.Width, node.Height), fill, col, node.Dashed)
+
+	lineHeight := scene.LineHeight()
+	centerX := node.X + node.Width/2
+	// vertically center the block of lines within the node
+	firstY := node.Y + node.Height/2 - lineHeight*float64(len(node.Lines)-1)/2
+
+	for i, line := range node.Lines {
+		f := regular
+		if i == 0 {
+			f = bold
+		}
+
+		face, err := newFace(f, scene.FontSize*c.scale)
+		if err != nil {
+			return fmt.Errorf("failed to create font face: %w", err)
+		}
+
+		c.drawText(face, line, centerX, firstY+float64(i)*lineHeight, textColor)
+		face.Close()
+	}
+
+	return nil
+}
+
+// drawText draws text centered horizontally and vertically around x/y,
+// given in diagram units.
+func (c *canvas) drawText(face font.Face, text string, x, y float64, col color.Color) {
+	metrics := face.Metrics()
+	width := toFloat(font.MeasureString(face, text))
+	baseline := y*c.scale + (toFloat(metrics.Ascent)-toFloat(metrics.Descent))/2
+
+	d := &font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(col),
+		Face: face,
+		Dot:  fixed.Point26_6{X: fixed.Int26_6((x*c.scale - width/2) * 64), Y: fixed.Int26_6(baseline * 64)},
+	}
+	d.DrawString(text)
+}
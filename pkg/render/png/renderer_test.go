@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package png
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// samplePKI returns a PKI with one root ClusterIssuer, a number of CAs with
+// their own Issuers, and leaf certificates spread across the CAs.
+func samplePKI(cas int, leaves int) pkigraph.Graph {
+	pki := &types.PKI{
+		ClusterIssuers: []certmanagerv1.ClusterIssuer{{
+			ObjectMeta: metav1.ObjectMeta{Name: "root"},
+			Spec: certmanagerv1.IssuerSpec{
+				IssuerConfig: certmanagerv1.IssuerConfig{SelfSigned: &certmanagerv1.SelfSignedIssuer{}},
+			},
+		}},
+	}
+
+	for i := range cas {
+		name := fmt.Sprintf("ca-%d", i)
+
+		pki.Certificates = append(pki.Certificates, certmanagerv1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: certmanagerv1.CertificateSpec{
+				IsCA:       true,
+				SecretName: name,
+				IssuerRef:  cmmeta.ObjectReference{Name: "root", Kind: "ClusterIssuer"},
+			},
+		})
+
+		pki.Issuers = append(pki.Issuers, certmanagerv1.Issuer{
+			ObjectMeta: metav1.ObjectMeta{Name: name + "-issuer", Namespace: "default"},
+			Spec: certmanagerv1.IssuerSpec{
+				IssuerConfig: certmanagerv1.IssuerConfig{CA: &certmanagerv1.CAIssuer{SecretName: name}},
+			},
+		})
+	}
+
+	for i := range leaves {
+		name := fmt.Sprintf("leaf-certificate-%d", i)
+
+		pki.Certificates = append(pki.Certificates, certmanagerv1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: certmanagerv1.CertificateSpec{
+				SecretName: name,
+				IssuerRef:  cmmeta.ObjectReference{Name: fmt.Sprintf("ca-%d-issuer", i%cas)},
+			},
+		})
+	}
+
+	return pkigraph.NewFromPKI(pki, pkigraph.Options{})
+}
+
+func BenchmarkRender(b *testing.B) {
+	// 86 nodes, most of them in a single very wide row
+	graph := samplePKI(5, 75)
+	renderer := New(Options{})
+
+	b.ReportAllocs()
+
+	for range b.N {
+		if err := renderer.Render(context.Background(), io.Discard, graph); err != nil {
+			b.Fatalf("Failed to render: %v", err)
+		}
+	}
+}
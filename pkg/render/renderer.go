@@ -4,21 +4,115 @@
 package render
 
 import (
+	"context"
+	"io"
+	"os"
 	"slices"
+	"strings"
 
 	"github.com/spf13/pflag"
 
 	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/theme"
 )
 
+// Renderer turns a PKI graph into a specific output format. Each renderer
+// instance carries its own options, so multiple instances of the same
+// renderer with different settings can be used at the same time.
 type Renderer interface {
+	// Render writes the graph to w. Implementations should abort early
+	// when the context is cancelled.
+	Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error
+	// AddFlags adds flags to set the renderer's options; flag names must be
+	// prefixed with the name the renderer is registered as.
+	AddFlags(fs *pflag.FlagSet)
+	// ValidateFlags is called after flags have been parsed.
+	ValidateFlags() error
+}
+
+// ThemedRenderer is implemented by renderers that style their output using
+// a theme.
+type ThemedRenderer interface {
+	Renderer
+	// WithTheme returns a copy of the renderer that uses the given theme.
+	WithTheme(th *theme.Theme) Renderer
+}
+
+// LintingRenderer is implemented by renderers that include lint findings in
+// their output.
+type LintingRenderer interface {
+	Renderer
+	// WithDisabledLintRules returns a copy of the renderer that skips the
+	// given lint rules.
+	WithDisabledLintRules(rules []string) Renderer
+}
+
+// StringRenderer is the original renderer interface which returned the entire
+// output as a string.
+//
+// Deprecated: Implement Renderer instead; until then, existing implementations
+// can be registered by wrapping them with FromStringRenderer.
+type StringRenderer interface {
 	RenderGraph(pki pkigraph.Graph) (string, error)
 	AddFlags(fs *pflag.FlagSet)
 	ValidateFlags() error
 }
 
+type stringRenderer struct {
+	StringRenderer
+}
+
+// FromStringRenderer adapts a StringRenderer to the Renderer interface.
+func FromStringRenderer(r StringRenderer) Renderer {
+	return &stringRenderer{StringRenderer: r}
+}
+
+func (r *stringRenderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rendered, err := r.RenderGraph(pki)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, rendered)
+	return err
+}
+
+// ToString renders the graph into a string.
+func ToString(ctx context.Context, r Renderer, pki pkigraph.Graph) (string, error) {
+	var buf strings.Builder
+	if err := r.Render(ctx, &buf, pki); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// IsTerminal returns true if w is a terminal (and not e.g. a pipe or file).
+// Renderers can use this to decide whether to use ANSI colors; writers
+// wrapping a terminal (like a bufio.Writer) are not detected.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
 var renderers = map[string]Renderer{}
 
+// Register makes a renderer available under the given name (e.g. for the
+// --format flag). Renderers using the old string-based interface must be
+// wrapped using FromStringRenderer.
 func Register(name string, r Renderer) {
 	renderers[name] = r
 }
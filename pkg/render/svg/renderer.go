@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package svg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/diagram"
+	"go.xrstf.de/pkiplot/pkg/layout"
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/theme"
+	"go.xrstf.de/pkiplot/pkg/types"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type Options struct {
+	// ShowType includes a node's type in its label.
+	ShowType bool
+	// FontSize is the label font size in pixels.
+	FontSize float64
+	// Theme styles the output; defaults to theme.Default.
+	Theme *theme.Theme
+}
+
+type renderer struct {
+	opt Options
+}
+
+var _ render.ThemedRenderer = &renderer{}
+
+func New(opt Options) *renderer {
+	if opt.FontSize == 0 {
+		opt.FontSize = diagram.DefaultFontSize
+	}
+
+	if opt.Theme == nil {
+		opt.Theme = theme.Default
+	}
+
+	return &renderer{opt: opt}
+}
+
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	opt := r.opt
+	opt.Theme = th
+
+	return New(opt)
+}
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVarP(&r.opt.ShowType, "svg-show-type", "", r.opt.ShowType, "SVG: include a node's type in the node label")
+	fs.Float64VarP(&r.opt.FontSize, "svg-font-size", "", r.opt.FontSize, "SVG: font size of node labels in pixels")
+}
+
+func (r *renderer) ValidateFlags() error {
+	if r.opt.FontSize <= 0 {
+		return errors.New("font size must be positive")
+	}
+
+	return nil
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	opt := diagram.NewDefaultOptions()
+	opt.ShowType = r.opt.ShowType
+	opt.FontSize = r.opt.FontSize
+	opt.Theme = r.opt.Theme
+
+	scene, err := diagram.Build(pki, opt)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	buf := types.NewErrWriter(w)
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	buf.Printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="sans-serif" font-size="%s">`+"\n",
+		num(scene.Width), num(scene.Height), num(scene.Width), num(scene.Height), num(scene.FontSize))
+
+	// arrow heads cannot inherit the edge color, so there is one marker per color
+	markers := map[string]string{}
+	for _, edge := range scene.Edges {
+		if _, exists := markers[edge.Color]; !exists {
+			markers[edge.Color] = fmt.Sprintf("arrow-%d", len(markers))
+		}
+	}
+
+	buf.WriteString("\t<defs>\n")
+	for _, col := range sets.List(sets.KeySet(markers)) {
+		buf.Printf("\t\t<marker id=\"%s\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"8\" markerHeight=\"8\" orient=\"auto-start-reverse\"><path d=\"M 0 0 L 10 5 L 0 10 z\" fill=\"%s\"/></marker>\n", markers[col], col)
+	}
+	buf.WriteString("\t</defs>\n")
+	buf.Printf("\t<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+
+	// draw edges first, so nodes are on top of them
+	buf.WriteString("\t<g class=\"edges\" fill=\"none\" stroke-width=\"1.5\">\n")
+	for _, edge := range scene.Edges {
+		dash := ""
+		if edge.Dashed {
+			dash = ` stroke-dasharray="5 3"`
+		}
+
+		buf.Printf("\t\t<path d=\"%s\" stroke=\"%s\"%s marker-end=\"url(#%s)\"/>\n", edgePath(edge.Points), edge.Color, dash, markers[edge.Color])
+	}
+	buf.WriteString("\t</g>\n")
+
+	buf.WriteString("\t<g class=\"nodes\">\n")
+	for _, node := range scene.Nodes {
+		writeNode(buf, scene, node)
+	}
+	buf.WriteString("\t</g>\n")
+
+	buf.WriteString("</svg>\n")
+
+	return buf.Err()
+}
+
+func writeNode(buf *types.ErrWriter, scene *diagram.Scene, node diagram.Node) {
+	dash := ""
+	if node.Dashed {
+		dash = ` stroke-dasharray="5 3"`
+	}
+
+	buf.Printf("\t\t<g class=\"%s\">\n", node.Class)
+	buf.Printf("\t\t\t<title>%s</title>\n", html.EscapeString(node.Title()))
+	buf.Printf("\t\t\t%s fill=\"%s\" stroke=\"%s\" stroke-width=\"2\"%s/>\n", shapeElement(node), node.Fill, node.Color, dash)
+
+	lineHeight := scene.LineHeight()
+	centerX := node.X + node.Width/2
+	// vertically center the block of lines within the node
+	firstY := node.Y + node.Height/2 - lineHeight*float64(len(node.Lines)-1)/2
+
+	for i, line := range node.Lines {
+		weight := ""
+		if i == 0 {
+			weight = ` font-weight="bold"`
+		}
+
+		buf.Printf("\t\t\t<text x=\"%s\" y=\"%s\" text-anchor=\"middle\" dominant-baseline=\"central\" fill=\"%s\"%s>%s</text>\n",
+			num(centerX), num(firstY+float64(i)*lineHeight), diagram.TextColor, weight, html.EscapeString(line))
+	}
+
+	buf.WriteString("\t\t</g>\n")
+}
+
+// shapeElement returns the opening of the SVG element for the node's shape,
+// without its style attributes.
+func shapeElement(node diagram.Node) string {
+	x, y, w, h := node.X, node.Y, node.Width, node.Height
+
+	switch node.Shape {
+	case theme.ShapeRectangle:
+		return fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s"`, num(x), num(y), num(w), num(h))
+
+	case theme.ShapeRounded:
+		return fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s" rx="%s"`, num(x), num(y), num(w), num(h), num(diagram.RoundedRadius))
+
+	case theme.ShapeHexagon:
+		points := []layout.Point{
+			{X: x, Y: y + h/2},
+			{X: x + diagram.HexagonInset, Y: y},
+			{X: x + w - diagram.HexagonInset, Y: y},
+			{X: x + w, Y: y + h/2},
+			{X: x + w - diagram.HexagonInset, Y: y + h},
+			{X: x + diagram.HexagonInset, Y: y + h},
+		}
+
+		coords := make([]string, 0, len(points))
+		for _, p := range points {
+			coords = append(coords, num(p.X)+","+num(p.Y))
+		}
+
+		return fmt.Sprintf(`<polygon points="%s"`, strings.Join(coords, " "))
+
+	case theme.ShapeCircle:
+		return fmt.Sprintf(`<ellipse cx="%s" cy="%s" rx="%s" ry="%s"`, num(x+w/2), num(y+h/2), num(w/2), num(h/2))
+
+	default:
+		return fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s" rx="%s"`, num(x), num(y), num(w), num(h), num(h/2))
+	}
+}
+
+// edgePath connects the points with cubic curves that leave and enter each
+// point vertically, which suits the top-to-bottom layout.
+func edgePath(points []layout.Point) string {
+	if len(points) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "M %s %s", num(points[0].X), num(points[0].Y))
+
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1], points[i]
+		midY := (from.Y + to.Y) / 2
+
+		fmt.Fprintf(&b, " C %s %s, %s %s, %s %s", num(from.X), num(midY), num(to.X), num(midY), num(to.X), num(to.Y))
+	}
+
+	return b.String()
+}
+
+// num formats coordinates compactly and without exponents.
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,234 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package tree
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/theme"
+	"go.xrstf.de/pkiplot/pkg/types"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type Options struct {
+	// NoColor disables ANSI colors. Colors are only used when writing to a
+	// terminal and the NO_COLOR environment variable is not set.
+	NoColor bool
+	// Theme styles the output; defaults to theme.Default.
+	Theme *theme.Theme
+}
+
+type renderer struct {
+	opt Options
+}
+
+var _ render.ThemedRenderer = &renderer{}
+
+func New(opt Options) *renderer {
+	if opt.Theme == nil {
+		opt.Theme = theme.Default
+	}
+
+	return &renderer{opt: opt}
+}
+
+func (r *renderer) WithTheme(th *theme.Theme) render.Renderer {
+	opt := r.opt
+	opt.Theme = th
+
+	return New(opt)
+}
+
+// classColors are ANSI color codes resembling the Mermaid classDefs.
+var classColors = map[string]string{
+	"clusterissuer": "92",
+	"issuer":        "94",
+	"ca":            "91",
+	"certificate":   "33",
+	"secret":        "31",
+}
+
+// classOrder determines the order of siblings, so that trust anchors come
+// before the things they sign.
+var classOrder = []string{"clusterissuer", "issuer", "ca", "certificate", "secret"}
+
+const (
+	ansiReset = "\033[0m"
+	ansiDim   = "\033[2m"
+)
+
+func (r *renderer) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVarP(&r.opt.NoColor, "tree-no-color", "", r.opt.NoColor, "Tree: do not use ANSI colors (only used when writing to a terminal and NO_COLOR is not set)")
+}
+
+func (r *renderer) ValidateFlags() error {
+	return nil
+}
+
+type treeWriter struct {
+	buf      *types.ErrWriter
+	graph    pkigraph.Graph
+	children map[string][]pkigraph.Node
+	visited  sets.Set[string]
+	theme    *theme.Theme
+	color    bool
+}
+
+func (r *renderer) Render(ctx context.Context, w io.Writer, pki pkigraph.Graph) error {
+	amap, err := pki.Raw().AdjacencyMap()
+	if err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+
+	tw := &treeWriter{
+		buf:      types.NewErrWriter(w),
+		graph:    pki,
+		children: map[string][]pkigraph.Node{},
+		visited:  sets.New[string](),
+		theme:    r.opt.Theme,
+		color:    !r.opt.NoColor && os.Getenv("NO_COLOR") == "" && render.IsTerminal(w),
+	}
+
+	var (
+		nodes []pkigraph.Node
+		roots []pkigraph.Node
+	)
+
+	for nodeHash, dependencies := range amap {
+		node, err := pki.Raw().Vertex(nodeHash)
+		if err != nil {
+			return fmt.Errorf("inconsistent graph: %w", err)
+		}
+
+		nodes = append(nodes, node)
+
+		// nodes that depend on nothing are the trust anchors
+		if len(dependencies) == 0 {
+			roots = append(roots, node)
+		}
+
+		// To have the tree start at the issuers, we reverse the edge direction here.
+		for dependency := range dependencies {
+			tw.children[dependency] = append(tw.children[dependency], node)
+		}
+	}
+
+	sortNodes(roots)
+	sortNodes(nodes)
+
+	for hash := range tw.children {
+		sortNodes(tw.children[hash])
+	}
+
+	for _, root := range roots {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tw.writeNode(root, "", "")
+	}
+
+	// nodes in cycles have no root and would otherwise be missing
+	for _, node := range nodes {
+		if !tw.visited.Has(node.Hash()) {
+			tw.writeNode(node, "", "")
+		}
+	}
+
+	return tw.buf.Err()
+}
+
+// writeNode prints a node and its descendants; prefix is used for the
+// node's own line and childPrefix for all lines below it.
+func (tw *treeWriter) writeNode(node pkigraph.Node, prefix, childPrefix string) {
+	hash := node.Hash()
+
+	tw.buf.Printf("%s%s", prefix, tw.label(node))
+
+	// nodes with multiple parents are only expanded once
+	if tw.visited.Has(hash) {
+		tw.buf.WriteString(tw.dim(" (see above)") + "\n")
+		return
+	}
+
+	tw.buf.WriteString("\n")
+	tw.visited.Insert(hash)
+
+	children := tw.children[hash]
+	for i, child := range children {
+		if i == len(children)-1 {
+			tw.writeNode(child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			tw.writeNode(child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+func (tw *treeWriter) label(node pkigraph.Node) string {
+	lines := tw.graph.Label(node)
+
+	name := lines[0]
+	if ns := node.Object().GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+
+	details := node.TypeName()
+	if node.Synthetic {
+		details += ", not found"
+	}
+
+	// additional label lines are shown alongside the type
+	for _, line := range lines[1:] {
+		details += ", " + line
+	}
+
+	if tw.color {
+		name = fmt.Sprintf("\033[%sm%s%s", tw.nodeColor(node), name, ansiReset)
+	}
+
+	return fmt.Sprintf("%s %s", name, tw.dim("("+details+")"))
+}
+
+// nodeColor returns the ANSI color code for a node. The default theme uses
+// the basic terminal colors, all other themes need 24-bit color support.
+func (tw *treeWriter) nodeColor(node pkigraph.Node) string {
+	th := tw.theme
+	if th == theme.Default {
+		return classColors[node.Class()]
+	}
+
+	col, _, err := th.NodeStyle(node).Colors()
+	if err != nil {
+		return classColors[node.Class()]
+	}
+
+	return fmt.Sprintf("38;2;%d;%d;%d", col.R, col.G, col.B)
+}
+
+func (tw *treeWriter) dim(s string) string {
+	if !tw.color {
+		return s
+	}
+
+	return ansiDim + s + ansiReset
+}
+
+func sortNodes(nodes []pkigraph.Node) {
+	slices.SortFunc(nodes, func(a, b pkigraph.Node) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(classOrder, a.Class()), slices.Index(classOrder, b.Class())),
+			cmp.Compare(a.Hash(), b.Hash()),
+		)
+	})
+}
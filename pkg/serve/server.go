@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package serve
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/render"
+	"go.xrstf.de/pkiplot/pkg/theme"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// LoadFunc loads the PKI from its sources; it is called initially and
+// whenever the server is told to reload.
+type LoadFunc func(ctx context.Context) (pkigraph.Graph, error)
+
+// Server serves an HTML preview of the PKI and its exports. Clients are
+// notified using server-sent events whenever the PKI was reloaded.
+type Server struct {
+	load  LoadFunc
+	theme *theme.Theme
+
+	lock        sync.RWMutex
+	graph       pkigraph.Graph
+	loadErr     error
+	generation  int
+	subscribers map[chan int]struct{}
+}
+
+// New returns a server that styles the served formats using the given theme.
+func New(load LoadFunc, th *theme.Theme) *Server {
+	return &Server{
+		load:        load,
+		theme:       th,
+		subscribers: map[chan int]struct{}{},
+	}
+}
+
+// Reload loads the PKI again and notifies all connected clients. Load errors
+// are shown to the clients and also returned.
+func (s *Server) Reload(ctx context.Context) error {
+	graph, err := s.load(ctx)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.loadErr = err
+	if err == nil {
+		s.graph = graph
+	}
+	s.generation++
+
+	for subscriber := range s.subscribers {
+		// subscribers only need to know that something changed, so if there
+		// is already a pending notification, there is no need to block
+		select {
+		case subscriber <- s.generation:
+		default:
+		}
+	}
+
+	return err
+}
+
+func (s *Server) Handler() http.Handler {
+	static, err := fs.Sub(assets, "assets")
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /{$}", serveFile(static, "index.html"))
+	mux.Handle("GET /assets/", http.StripPrefix("/assets/", http.FileServerFS(static)))
+	mux.Handle("GET /pki.svg", s.serveFormat("svg", "image/svg+xml"))
+	mux.Handle("GET /pki.mmd", s.serveFormat("mermaid", "text/plain; charset=utf-8"))
+	mux.Handle("GET /pki.dot", s.serveFormat("graphviz", "text/vnd.graphviz; charset=utf-8"))
+	mux.Handle("GET /pki.json", s.serveFormat("json", "application/json"))
+	mux.HandleFunc("GET /events", s.serveEvents)
+
+	return mux
+}
+
+func serveFile(fsys fs.FS, name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, fsys, name)
+	})
+}
+
+func (s *Server) serveFormat(format string, contentType string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		renderer, ok := render.Get(format)
+		if !ok {
+			http.Error(w, fmt.Sprintf("format %q is not available", format), http.StatusNotFound)
+			return
+		}
+
+		if themed, ok := renderer.(render.ThemedRenderer); ok && s.theme != nil {
+			renderer = themed.WithTheme(s.theme)
+		}
+
+		s.lock.RLock()
+		graph, loadErr := s.graph, s.loadErr
+		s.lock.RUnlock()
+
+		if loadErr != nil {
+			http.Error(w, loadErr.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// render into a buffer first, so errors can still be reported properly
+		var buf bytes.Buffer
+		if err := renderer.Render(r.Context(), &buf, graph); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "no-store")
+		if _, err := buf.WriteTo(w); err != nil {
+			log.Printf("Failed to send %s response: %v", format, err)
+		}
+	})
+}
+
+const eventKeepAlive = 30 * time.Second
+
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	updates := make(chan int, 1)
+
+	s.lock.Lock()
+	s.subscribers[updates] = struct{}{}
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.subscribers, updates)
+		s.lock.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		var err error
+
+		select {
+		case <-r.Context().Done():
+			return
+
+		case generation := <-updates:
+			_, err = fmt.Fprintf(w, "event: reload\ndata: %d\n\n", generation)
+
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		}
+
+		if err != nil {
+			return
+		}
+
+		flusher.Flush()
+	}
+}
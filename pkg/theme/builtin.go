@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package theme
+
+import (
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// DefaultName is the name of the default theme.
+const DefaultName = "default"
+
+// Default resembles the colors pkiplot has always used.
+var Default = &Theme{
+	Classes: map[string]Style{
+		"clusterissuer": {Color: "#7F7"},
+		"issuer":        {Color: "#77F"},
+		"ca":            {Color: "#F77"},
+		"certificate":   {Color: "orange"},
+		"secret":        {Color: "red"},
+	},
+	States: map[string]Style{
+		StateSynthetic: {Dashed: true},
+		StateDrifted:   {Color: "purple"},
+		StateExpiring:  {Color: "red", Fill: "#FDD"},
+	},
+}
+
+// Colorblind uses the Okabe-Ito palette, which is distinguishable with all
+// common forms of color blindness, and gives every class its own shape.
+var Colorblind = &Theme{
+	Classes: map[string]Style{
+		"clusterissuer": {Color: "#009E73", Shape: ShapeHexagon},
+		"issuer":        {Color: "#0072B2", Shape: ShapeHexagon},
+		"ca":            {Color: "#D55E00", Shape: ShapeRounded},
+		"certificate":   {Color: "#E69F00", Shape: ShapeStadium},
+		"secret":        {Color: "#CC79A7", Shape: ShapeRectangle},
+	},
+	States: map[string]Style{
+		StateSynthetic: {Dashed: true},
+		StateDrifted:   {Color: "#000"},
+		StateExpiring:  {Fill: "#F0E442"},
+	},
+	Edges: map[string]Style{
+		string(pkigraph.EdgeIssues):  {Color: "#555"},
+		string(pkigraph.EdgeCreates): {Color: "#999", Dashed: true},
+		string(pkigraph.EdgeCAFor):   {Color: "#D55E00"},
+	},
+}
+
+// Monochrome only uses shades of gray and shapes, e.g. for printing.
+var Monochrome = &Theme{
+	Classes: map[string]Style{
+		"clusterissuer": {Color: "#000", Fill: "#DDD", Shape: ShapeHexagon},
+		"issuer":        {Color: "#000", Fill: "#FFF", Shape: ShapeHexagon},
+		"ca":            {Color: "#000", Fill: "#DDD", Shape: ShapeRounded},
+		"certificate":   {Color: "#000", Fill: "#FFF", Shape: ShapeStadium},
+		"secret":        {Color: "#000", Fill: "#FFF", Shape: ShapeRectangle},
+	},
+	States: map[string]Style{
+		StateSynthetic: {Dashed: true},
+		StateDrifted:   {Fill: "#AAA"},
+		StateExpiring:  {Fill: "#777"},
+	},
+	Edges: map[string]Style{
+		string(pkigraph.EdgeIssues):  {Color: "#000"},
+		string(pkigraph.EdgeCreates): {Color: "#000", Dashed: true},
+		string(pkigraph.EdgeCAFor):   {Color: "#000"},
+	},
+}
+
+var builtin = map[string]*Theme{
+	DefaultName:  Default,
+	"colorblind": Colorblind,
+	"monochrome": Monochrome,
+}
+
+// Names returns the names of all built-in themes.
+func Names() []string {
+	return sets.List(sets.KeySet(builtin))
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package theme
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// namedColors are the CSS color names used by the default styles.
+var namedColors = map[string]color.RGBA{
+	"black":  {0x00, 0x00, 0x00, 0xFF},
+	"white":  {0xFF, 0xFF, 0xFF, 0xFF},
+	"red":    {0xFF, 0x00, 0x00, 0xFF},
+	"green":  {0x00, 0x80, 0x00, 0xFF},
+	"blue":   {0x00, 0x00, 0xFF, 0xFF},
+	"orange": {0xFF, 0xA5, 0x00, 0xFF},
+	"yellow": {0xFF, 0xFF, 0x00, 0xFF},
+	"purple": {0x80, 0x00, 0x80, 0xFF},
+	"gray":   {0x80, 0x80, 0x80, 0xFF},
+	"grey":   {0x80, 0x80, 0x80, 0xFF},
+}
+
+// ParseColor parses CSS-style colors in the form of "#rgb", "#rrggbb" or a
+// basic color name, for renderers that cannot use CSS directly.
+func ParseColor(s string) (color.RGBA, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if c, ok := namedColors[s]; ok {
+		return c, nil
+	}
+
+	hex, found := strings.CutPrefix(s, "#")
+	if !found {
+		return color.RGBA{}, fmt.Errorf("unknown color %q", s)
+	}
+
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xFF}, nil
+}
+
+// Tint mixes the color with white, with alpha being the share of the color.
+func Tint(c color.RGBA, alpha float64) color.RGBA {
+	mix := func(v uint8) uint8 {
+		return uint8(float64(v)*alpha + 255*(1-alpha) + 0.5)
+	}
+
+	return color.RGBA{R: mix(c.R), G: mix(c.G), B: mix(c.B), A: 0xFF}
+}
+
+// Hex returns the color in the #RRGGBB form.
+func Hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
@@ -0,0 +1,318 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package theme maps nodes and edges to colors and shapes. Themes are either
+// built-in or loaded from YAML files and are used by all renderers that
+// style their output.
+package theme
+
+import (
+	"cmp"
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+	"time"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// Node states that themes can style on top of a node's class.
+const (
+	// StateSynthetic nodes were only referenced, but not found in the sources.
+	StateSynthetic = "synthetic"
+	// StateDrifted Certificates are not ready or about to be re-issued,
+	// according to their status.
+	StateDrifted = "drifted"
+	// StateExpiring Certificates expire within ExpiringWithin.
+	StateExpiring = "expiring"
+)
+
+// States are all node states, in the order they are applied.
+var States = []string{StateSynthetic, StateDrifted, StateExpiring}
+
+// Shapes that themes can use; renderers that cannot draw a shape fall back
+// to their default shape.
+const (
+	ShapeStadium   = "stadium"
+	ShapeRectangle = "rectangle"
+	ShapeRounded   = "rounded"
+	ShapeHexagon   = "hexagon"
+	ShapeCircle    = "circle"
+)
+
+var shapes = []string{ShapeStadium, ShapeRectangle, ShapeRounded, ShapeHexagon, ShapeCircle}
+
+const (
+	// DefaultColor is used for nodes and edges without a color.
+	DefaultColor = "#555"
+	// FillOpacity is used to tint node backgrounds with their color.
+	FillOpacity = 0.15
+)
+
+// ExpiringWithin is how close to its notAfter date a Certificate is
+// considered expiring.
+var ExpiringWithin = 30 * 24 * time.Hour
+
+// Style is a partial style; empty fields are inherited from less specific
+// styles (e.g. a state's style is applied on top of the class style).
+type Style struct {
+	// Color is used for borders and lines.
+	Color string `json:"color,omitempty"`
+	// Fill is the background color, which defaults to a light tint of Color.
+	Fill   string `json:"fill,omitempty"`
+	Shape  string `json:"shape,omitempty"`
+	Dashed bool   `json:"dashed,omitempty"`
+}
+
+// Colors parses the style's colors. The fill color defaults to a light tint
+// of the line color.
+func (s Style) Colors() (line color.RGBA, fill color.RGBA, err error) {
+	line, err = ParseColor(cmp.Or(s.Color, DefaultColor))
+	if err != nil {
+		return line, fill, err
+	}
+
+	if s.Fill == "" {
+		return line, Tint(line, FillOpacity), nil
+	}
+
+	fill, err = ParseColor(s.Fill)
+
+	return line, fill, err
+}
+
+func (s Style) merge(overlay Style) Style {
+	if overlay.Color != "" {
+		s.Color = overlay.Color
+	}
+
+	if overlay.Fill != "" {
+		s.Fill = overlay.Fill
+	}
+
+	if overlay.Shape != "" {
+		s.Shape = overlay.Shape
+	}
+
+	s.Dashed = s.Dashed || overlay.Dashed
+
+	return s
+}
+
+type Theme struct {
+	// Extends names a built-in theme whose styles are used unless
+	// overridden by this theme.
+	Extends string `json:"extends,omitempty"`
+
+	// Classes are keyed by node class (clusterissuer, issuer, ca,
+	// certificate and secret).
+	Classes map[string]Style `json:"classes,omitempty"`
+	// IssuerTypes are applied to Issuers and ClusterIssuers on top of their
+	// class style and keyed by type (ca, selfSigned, acme, vault, venafi).
+	IssuerTypes map[string]Style `json:"issuerTypes,omitempty"`
+	// States are applied on top of the class and issuer type styles.
+	States map[string]Style `json:"states,omitempty"`
+	// Edges are keyed by edge type (issues, creates, CA for).
+	Edges map[string]Style `json:"edges,omitempty"`
+}
+
+// Load reads a theme file.
+func Load(filename string) (*Theme, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Theme{}
+	if err := yaml.UnmarshalStrict(content, t); err != nil {
+		return nil, err
+	}
+
+	if t.Extends != "" {
+		base, ok := builtin[t.Extends]
+		if !ok {
+			return nil, fmt.Errorf("cannot extend unknown theme %q, must be one of %v", t.Extends, Names())
+		}
+
+		t = base.extend(t)
+	}
+
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Get returns a built-in theme or loads the given theme file.
+func Get(nameOrFile string) (*Theme, error) {
+	if t, ok := builtin[nameOrFile]; ok {
+		return t, nil
+	}
+
+	if !strings.ContainsAny(nameOrFile, `./\`) {
+		return nil, fmt.Errorf("unknown theme %q, must be one of %v or a file", nameOrFile, Names())
+	}
+
+	return Load(nameOrFile)
+}
+
+// extend returns a copy of t with all styles from the other theme
+// replacing its own.
+func (t *Theme) extend(other *Theme) *Theme {
+	merged := &Theme{}
+
+	mergeStyles := func(base, overlay map[string]Style) map[string]Style {
+		result := map[string]Style{}
+		for key, style := range base {
+			result[key] = style
+		}
+		for key, style := range overlay {
+			result[key] = result[key].merge(style)
+		}
+		return result
+	}
+
+	merged.Classes = mergeStyles(t.Classes, other.Classes)
+	merged.IssuerTypes = mergeStyles(t.IssuerTypes, other.IssuerTypes)
+	merged.States = mergeStyles(t.States, other.States)
+	merged.Edges = mergeStyles(t.Edges, other.Edges)
+
+	return merged
+}
+
+func (t *Theme) validate() error {
+	check := func(kind string, styles map[string]Style, keys []string) error {
+		for _, key := range sets.List(sets.KeySet(styles)) {
+			if keys != nil && !sets.New(keys...).Has(key) {
+				return fmt.Errorf("unknown %s %q, must be one of %v", kind, key, keys)
+			}
+
+			style := styles[key]
+
+			for _, c := range []string{style.Color, style.Fill} {
+				if c == "" {
+					continue
+				}
+
+				if _, err := ParseColor(c); err != nil {
+					return fmt.Errorf("%s %q: %w", kind, key, err)
+				}
+			}
+
+			if style.Shape != "" && !sets.New(shapes...).Has(style.Shape) {
+				return fmt.Errorf("%s %q: unknown shape %q, must be one of %v", kind, key, style.Shape, shapes)
+			}
+		}
+
+		return nil
+	}
+
+	if err := check("class", t.Classes, pkigraph.Classes); err != nil {
+		return err
+	}
+
+	if err := check("issuer type", t.IssuerTypes, nil); err != nil {
+		return err
+	}
+
+	if err := check("state", t.States, States); err != nil {
+		return err
+	}
+
+	edgeTypes := []string{string(pkigraph.EdgeIssues), string(pkigraph.EdgeCreates), string(pkigraph.EdgeCAFor)}
+
+	return check("edge type", t.Edges, edgeTypes)
+}
+
+// ClassStyle returns the style for a node class.
+func (t *Theme) ClassStyle(class string) Style {
+	return t.Classes[class]
+}
+
+// IssuerTypeStyle returns the additional style for an issuer type.
+func (t *Theme) IssuerTypeStyle(issuerType string) (Style, bool) {
+	style, ok := t.IssuerTypes[issuerType]
+	return style, ok
+}
+
+// StateStyle returns the additional style for a node state.
+func (t *Theme) StateStyle(state string) (Style, bool) {
+	style, ok := t.States[state]
+	return style, ok
+}
+
+// NodeStyle returns the fully resolved style for a node.
+func (t *Theme) NodeStyle(node pkigraph.Node) Style {
+	style := t.ClassStyle(node.Class())
+
+	if overlay, ok := t.IssuerTypeStyle(node.IssuerType()); ok {
+		style = style.merge(overlay)
+	}
+
+	for _, state := range NodeStates(node) {
+		if overlay, ok := t.StateStyle(state); ok {
+			style = style.merge(overlay)
+		}
+	}
+
+	return style
+}
+
+// EdgeStyle returns the style for an edge type.
+func (t *Theme) EdgeStyle(edgeType pkigraph.EdgeType) Style {
+	return t.Edges[string(edgeType)]
+}
+
+// IssuerTypeClass returns the class for Issuers and ClusterIssuers of a
+// type, e.g. "issuer_acme".
+func IssuerTypeClass(issuerType string) string {
+	return "issuer_" + strings.ToLower(issuerType)
+}
+
+// NodeClasses returns the class names renderers assign to a node: its class
+// (see Node.Class), followed by its issuer type class (if any) and its
+// states.
+func NodeClasses(node pkigraph.Node) []string {
+	classes := []string{node.Class()}
+
+	if issuerType := node.IssuerType(); issuerType != "" {
+		classes = append(classes, IssuerTypeClass(issuerType))
+	}
+
+	return append(classes, NodeStates(node)...)
+}
+
+// NodeStates returns the states a node is in, in the order of States.
+func NodeStates(node pkigraph.Node) []string {
+	var states []string
+
+	if node.Synthetic {
+		states = append(states, StateSynthetic)
+	}
+
+	if cert := node.Certificate; cert != nil && !node.Synthetic {
+		for _, cond := range cert.Status.Conditions {
+			notReady := cond.Type == certmanagerv1.CertificateConditionReady && cond.Status == cmmeta.ConditionFalse
+			reissuing := cond.Type == certmanagerv1.CertificateConditionIssuing && cond.Status == cmmeta.ConditionTrue
+
+			if notReady || reissuing {
+				states = append(states, StateDrifted)
+				break
+			}
+		}
+
+		if notAfter := cert.Status.NotAfter; notAfter != nil && time.Until(notAfter.Time) < ExpiringWithin {
+			states = append(states, StateExpiring)
+		}
+	}
+
+	return states
+}
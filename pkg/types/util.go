@@ -5,6 +5,7 @@ package types
 
 import (
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -15,3 +16,39 @@ type StringBuilder struct {
 func (sb *StringBuilder) Printf(format string, args ...any) {
 	sb.WriteString(fmt.Sprintf(format, args...))
 }
+
+// ErrWriter wraps an io.Writer and remembers the first error that occurred,
+// turning all subsequent writes into no-ops. This saves callers from checking
+// the result of every single write.
+type ErrWriter struct {
+	w   io.Writer
+	err error
+}
+
+func NewErrWriter(w io.Writer) *ErrWriter {
+	return &ErrWriter{w: w}
+}
+
+func (ew *ErrWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+
+	n, err := ew.w.Write(p)
+	ew.err = err
+
+	return n, err
+}
+
+func (ew *ErrWriter) WriteString(s string) {
+	_, _ = io.WriteString(ew, s)
+}
+
+func (ew *ErrWriter) Printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(ew, format, args...)
+}
+
+// Err returns the first error that occurred while writing.
+func (ew *ErrWriter) Err() error {
+	return ew.err
+}
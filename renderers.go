@@ -1,9 +0,0 @@
-// SPDX-FileCopyrightText: 2023 Christoph Mewes
-// SPDX-License-Identifier: MIT
-
-package main
-
-import (
-	_ "go.xrstf.de/pkiplot/pkg/render/graphviz"
-	_ "go.xrstf.de/pkiplot/pkg/render/mermaid"
-)
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"go.xrstf.de/pkiplot/pkg/loader"
+	"go.xrstf.de/pkiplot/pkg/pkigraph"
+	"go.xrstf.de/pkiplot/pkg/pkiplot"
+	"go.xrstf.de/pkiplot/pkg/serve"
+	"go.xrstf.de/pkiplot/pkg/theme"
+)
+
+// runServer starts the preview server and reloads the PKI whenever a source
+// changes, until the context is cancelled.
+func runServer(ctx context.Context, listen string, args []string, sources []pkiplot.Source, plotOpts []pkiplot.Option, th *theme.Theme) error {
+	server := serve.New(func(ctx context.Context) (pkigraph.Graph, error) {
+		graph, _, err := pkiplot.Load(ctx, sources, plotOpts...)
+		return graph, err
+	}, th)
+
+	if err := server.Reload(ctx); err != nil {
+		log.Printf("Error: %v.", err)
+	}
+
+	watcher, err := newSourceWatcher(args, loader.NewDefaultOptions().FileExtensions, nil)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		err := watcher.Run(ctx, func() {
+			if err := server.Reload(ctx); err != nil {
+				log.Printf("Error: %v.", err)
+			} else {
+				log.Printf("PKI reloaded successfully.")
+			}
+		})
+		if err != nil {
+			log.Printf("Failed to watch sources: %v", err)
+		}
+	}()
+
+	httpServer := &http.Server{
+		Addr:              listen,
+		Handler:           server.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Serving PKI preview on http://%s/, press Ctrl+C to stop…", listen)
+
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
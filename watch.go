@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// watchDebounce is how long to wait for further changes before re-rendering,
+// as editors and `helm template > ...` usually cause a burst of events.
+const watchDebounce = 200 * time.Millisecond
+
+// sourceWatcher decides which filesystem events concern the sources.
+type sourceWatcher struct {
+	watcher *fsnotify.Watcher
+	// files are sources given explicitly
+	files sets.Set[string]
+	// dirs are source directories, all YAML files within them are relevant
+	dirs       []string
+	extensions []string
+	// ignored are files written by pkiplot itself
+	ignored sets.Set[string]
+}
+
+func newSourceWatcher(sources []string, extensions []string, outputs []string) (*sourceWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	sw := &sourceWatcher{
+		watcher:    watcher,
+		files:      sets.New[string](),
+		extensions: extensions,
+		ignored:    sets.New[string](),
+	}
+
+	for _, output := range outputs {
+		abs, err := filepath.Abs(output)
+		if err != nil {
+			return nil, err
+		}
+
+		sw.ignored.Insert(abs)
+	}
+
+	for _, source := range sources {
+		if source == "-" {
+			watcher.Close()
+			return nil, errors.New("stdin cannot be watched for changes")
+		}
+
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+
+		stat, err := os.Stat(abs)
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+
+		if stat.IsDir() {
+			sw.dirs = append(sw.dirs, abs)
+			err = sw.addDirectory(abs)
+		} else {
+			// Watch the parent directory instead of the file itself, as many
+			// editors replace files instead of writing to them.
+			sw.files.Insert(abs)
+			err = watcher.Add(filepath.Dir(abs))
+		}
+
+		if err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("failed to watch %s: %w", source, err)
+		}
+	}
+
+	return sw, nil
+}
+
+// addDirectory watches a directory and all of its subdirectories, as
+// fsnotify does not support recursive watches.
+func (sw *sourceWatcher) addDirectory(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return sw.watcher.Add(path)
+		}
+
+		return nil
+	})
+}
+
+func (sw *sourceWatcher) isRelevant(event fsnotify.Event) bool {
+	if event.Has(fsnotify.Chmod) || sw.ignored.Has(event.Name) {
+		return false
+	}
+
+	if sw.files.Has(event.Name) {
+		return true
+	}
+
+	for _, dir := range sw.dirs {
+		if event.Name != dir && !strings.HasPrefix(event.Name, dir+string(filepath.Separator)) {
+			continue
+		}
+
+		// newly created directories need to be watched as well
+		if stat, err := os.Stat(event.Name); err == nil && stat.IsDir() {
+			if event.Has(fsnotify.Create) {
+				if err := sw.addDirectory(event.Name); err != nil {
+					log.Printf("Warning: failed to watch %s: %v", event.Name, err)
+				}
+			}
+
+			return true
+		}
+
+		if hasAnyExtension(event.Name, sw.extensions) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasAnyExtension(filename string, extensions []string) bool {
+	ext := strings.TrimPrefix(filepath.Ext(filename), ".")
+
+	for _, candidate := range extensions {
+		if candidate == ext {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Run calls onChange whenever a source changed, until the context is cancelled.
+func (sw *sourceWatcher) Run(ctx context.Context, onChange func()) error {
+	defer sw.watcher.Close()
+
+	// a stopped timer whose channel is only used after the first Reset
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-sw.watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if sw.isRelevant(event) {
+				debounce.Reset(watchDebounce)
+			}
+
+		case err, ok := <-sw.watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			log.Printf("Warning: error while watching for changes: %v", err)
+
+		case <-debounce.C:
+			onChange()
+		}
+	}
+}
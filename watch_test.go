@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2025 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.xrstf.de/pkiplot/pkg/pkiplot"
+)
+
+const (
+	validPKI = `apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: ca
+  namespace: default
+spec:
+  selfSigned: {}
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: web
+  namespace: default
+spec:
+  secretName: web-tls
+  issuerRef:
+    name: ca
+`
+
+	brokenPKI = `apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: ca
+  namespace: default
+spec:
+  selfSigned: {}
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: web
+  namespace: default
+spec:
+  secretName: web-tls
+  issuerRef:
+    name: typo
+`
+)
+
+// syncBuffer collects log output written from the watcher goroutine.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.buf.String()
+}
+
+func TestWatchReportsLintFindings(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "pki.yaml")
+	output := filepath.Join(dir, "pki.mmd")
+
+	if err := os.WriteFile(source, []byte(validPKI), 0o644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	targets, err := parseOutputs([]string{"mermaid"}, output)
+	if err != nil {
+		t.Fatalf("Failed to parse outputs: %v", err)
+	}
+
+	var logs syncBuffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	sources := []pkiplot.Source{pkiplot.FromPath(source)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := plot(ctx, sources, nil, nil, targets); err != nil {
+		t.Fatalf("Failed to plot: %v", err)
+	}
+
+	if strings.Contains(logs.String(), "Lint:") {
+		t.Fatalf("Expected no lint findings for a valid PKI, got:\n%s", logs.String())
+	}
+
+	watcher, err := newSourceWatcher([]string{source}, []string{"yaml"}, []string{output})
+	if err != nil {
+		t.Fatalf("Failed to watch sources: %v", err)
+	}
+
+	cycles := make(chan error, 10)
+	go func() {
+		_ = watcher.Run(ctx, func() {
+			cycles <- plot(ctx, sources, nil, nil, targets)
+		})
+	}()
+
+	if err := os.WriteFile(source, []byte(brokenPKI), 0o644); err != nil {
+		t.Fatalf("Failed to update source: %v", err)
+	}
+
+	select {
+	case err := <-cycles:
+		if err != nil {
+			t.Fatalf("Failed to plot after change: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Source change did not trigger a re-render.")
+	}
+
+	expected := "Lint: Certificate default/web: The referenced Issuer `typo` is not part of the manifests. (missing-issuer)"
+	if !strings.Contains(logs.String(), expected) {
+		t.Fatalf("Expected log output to contain\n%s\n\ngot:\n%s", expected, logs.String())
+	}
+}